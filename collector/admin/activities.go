@@ -0,0 +1,153 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/cursor"
+	"github.com/jullianow/lcp-exporter/internal/events"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+type ActivitiesOptions struct {
+	Cursor      *cursor.Store
+	Sinks       []events.Sink
+	Concurrency int
+}
+
+type activitiesCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	options         ActivitiesOptions
+	now             func() time.Time
+
+	activities *prometheus.CounterVec
+}
+
+func NewActivitiesCollector(client *lcp.Client, provider ProjectProvider, options ActivitiesOptions) *activitiesCollector {
+	if options.Cursor == nil {
+		options.Cursor, _ = cursor.Open("")
+	}
+
+	return &activitiesCollector{
+		client:          client,
+		projectProvider: provider,
+		options:         options,
+		now:             time.Now,
+		activities: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: internal.Name("activities")("total"),
+				Help: "Total number of new activities by project and type",
+			},
+			[]string{"project_name", "type"},
+		),
+	}
+}
+
+func (ac *activitiesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ac.activities.Describe(ch)
+}
+
+func (ac *activitiesCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = ac.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (ac *activitiesCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := ac.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("ActivitiesCollector", "No projects found")
+		return nil
+	}
+
+	err = forEachProject(ctx, "ActivitiesCollector", projects, ac.options.Concurrency, ch, ac.tail)
+
+	if saveErr := ac.options.Cursor.Save(); saveErr != nil {
+		internal.LogError("ActivitiesCollector", "Failed to persist activity cursor: %v", saveErr)
+	}
+
+	ac.activities.Collect(ch)
+
+	return err
+}
+
+func (ac *activitiesCollector) tail(ctx context.Context, projectID string, _ chan<- prometheus.Metric) error {
+	position, ok := ac.options.Cursor.Get(projectID)
+
+	var queryParams map[string]string
+	if ok {
+		queryParams = map[string]string{"since": internal.IntToString(position.At)}
+	}
+
+	path := fmt.Sprintf("/projects/%s/activities", url.PathEscape(projectID))
+	activities, err := lcp.FetchFrom[shared.Activity](ctx, ac.client, path, queryParams)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].CreatedAt < activities[j].CreatedAt
+	})
+
+	// Without a cursor the feed is only used as a baseline, otherwise the
+	// whole history would be counted and forwarded on first start.
+	if !ok {
+		for _, activity := range activities {
+			position = position.Advance(activity.ID, activity.CreatedAt)
+		}
+		if position.At == 0 {
+			position.At = ac.now().UnixMilli()
+		}
+		ac.options.Cursor.Set(projectID, position)
+		internal.LogInfo("ActivitiesCollector", "Starting activity cursor for project %s at %d", projectID, position.At)
+		return nil
+	}
+
+	for _, activity := range activities {
+		if position.Seen(activity.ID, activity.CreatedAt) {
+			continue
+		}
+
+		ac.activities.WithLabelValues(projectID, strings.ToLower(activity.Type)).Inc()
+		ac.forward(ctx, projectID, activity)
+		position = position.Advance(activity.ID, activity.CreatedAt)
+	}
+
+	ac.options.Cursor.Set(projectID, position)
+	return nil
+}
+
+func (ac *activitiesCollector) forward(ctx context.Context, projectID string, activity shared.Activity) {
+	event := events.Event{
+		ID:        "activity/" + activity.ID,
+		Kind:      "activity",
+		ProjectID: projectID,
+		ServiceID: activity.ServiceID,
+		Timestamp: time.UnixMilli(activity.CreatedAt).UTC(),
+		Payload:   activity,
+	}
+
+	for _, sink := range ac.options.Sinks {
+		if err := sink.Send(ctx, event); err != nil {
+			internal.LogError("ActivitiesCollector", "Failed to forward activity %s to %s: %v", activity.ID, sink.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal/cursor"
+	"github.com/jullianow/lcp-exporter/internal/events"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestActivitiesCollector(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-prd", OrganizationId: "proj"}}
+
+	feed := []string{
+		`{"id": "a1", "projectId": "proj-prd", "type": "DEPLOY", "createdAt": 1740916800000}`,
+		`{"id": "a2", "projectId": "proj-prd", "type": "RESTART", "serviceId": "liferay", "createdAt": 1740916900000}`,
+	}
+
+	var mu sync.Mutex
+	var sinces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/projects/proj-prd/activities", r.URL.Path)
+
+		mu.Lock()
+		defer mu.Unlock()
+		sinces = append(sinces, r.URL.Query().Get("since"))
+
+		_, err := fmt.Fprintf(w, "[%s]", strings.Join(feed, ","))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cursor.json")
+	store, err := cursor.Open(path)
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewActivitiesCollector(client, projectProvider, ActivitiesOptions{
+		Cursor:      store,
+		Sinks:       []events.Sink{events.NewLogSink(&logs)},
+		Concurrency: 1,
+	})
+
+	update := func(c *activitiesCollector) {
+		ch := make(chan prometheus.Metric, 100)
+		require.NoError(t, c.Update(context.Background(), ch))
+	}
+
+	// The first poll only sets the baseline.
+	update(collector)
+	require.Equal(t, 0, testutil.CollectAndCount(collector.activities))
+	require.Empty(t, logs.String())
+
+	mu.Lock()
+	feed = append(feed,
+		`{"id": "a3", "projectId": "proj-prd", "type": "DEPLOY", "createdAt": 1740917000000}`,
+		`{"id": "a4", "projectId": "proj-prd", "type": "ENV_VAR_CHANGE", "serviceId": "liferay", "createdAt": 1740917000000}`,
+	)
+	mu.Unlock()
+
+	update(collector)
+	update(collector)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(collector.activities.WithLabelValues("proj-prd", "deploy")))
+	require.Equal(t, 1.0, testutil.ToFloat64(collector.activities.WithLabelValues("proj-prd", "env_var_change")))
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var event events.Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	require.Equal(t, "activity/a4", event.ID)
+	require.Equal(t, "activity", event.Kind)
+	require.Equal(t, "liferay", event.ServiceID)
+
+	// A restarted exporter resumes from the persisted cursor.
+	reopened, err := cursor.Open(path)
+	require.NoError(t, err)
+
+	restarted := NewActivitiesCollector(client, projectProvider, ActivitiesOptions{Cursor: reopened, Concurrency: 1})
+	update(restarted)
+	require.Equal(t, 0, testutil.CollectAndCount(restarted.activities))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"", "1740916900000", "1740917000000", "1740917000000"}, sinces)
+}
@@ -0,0 +1,156 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+type alertsCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	window          internal.DateWindow
+	concurrency     int
+
+	mu         sync.Mutex
+	seenAlerts map[string]int64
+
+	raised *prometheus.CounterVec
+
+	active *prometheus.Desc
+}
+
+func NewAlertsCollector(client *lcp.Client, provider ProjectProvider, window internal.DateWindow, concurrency int) *alertsCollector {
+	fqName := internal.Name("alerts")
+	labels := []string{"project", "service", "alert_type", "severity"}
+
+	return &alertsCollector{
+		client:          client,
+		projectProvider: provider,
+		window:          window,
+		concurrency:     concurrency,
+		seenAlerts:      make(map[string]int64),
+		raised: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fqName("raised_total"),
+				Help: "Total number of alerts raised by project, service, type and severity",
+			},
+			labels,
+		),
+		active: prometheus.NewDesc(
+			fqName("active"),
+			"Number of currently active alerts by project, service, type and severity",
+			labels,
+			nil,
+		),
+	}
+}
+
+func (ac *alertsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ac.active
+	ac.raised.Describe(ch)
+}
+
+func (ac *alertsCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = ac.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (ac *alertsCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := ac.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("AlertsCollector", "No projects found")
+		return nil
+	}
+
+	dataRange := ac.window.Range()
+	from := internal.DateRangeStartMillis(dataRange)
+	queryParams := map[string]string{
+		"start": dataRange.From,
+		"end":   dataRange.End,
+	}
+
+	err = forEachProject(ctx, "AlertsCollector", projects, ac.concurrency, ch, func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error {
+		path := fmt.Sprintf("/projects/%s/alerts", url.PathEscape(projectID))
+		raised, err := lcp.FetchFrom[shared.Alert](ctx, ac.client, path, queryParams)
+		if err != nil {
+			return err
+		}
+
+		// Active alerts are looked up without the window, which may not reach
+		// back to when they were raised.
+		active, err := lcp.FetchFrom[shared.Alert](ctx, ac.client, path, map[string]string{"status": "active"})
+		if err != nil {
+			return err
+		}
+
+		ac.collectRaised(projectID, raised, from)
+		ac.collectActive(ch, projectID, active)
+		return nil
+	})
+
+	ac.mu.Lock()
+	for key, at := range ac.seenAlerts {
+		if at < from {
+			delete(ac.seenAlerts, key)
+		}
+	}
+	ac.mu.Unlock()
+
+	ac.raised.Collect(ch)
+
+	return err
+}
+
+func (ac *alertsCollector) collectRaised(projectID string, alerts []shared.Alert, from int64) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	for _, alert := range alerts {
+		seenKey := projectID + "/" + alert.ID
+		if _, seen := ac.seenAlerts[seenKey]; seen || alert.CreatedAt < from {
+			continue
+		}
+		ac.seenAlerts[seenKey] = alert.CreatedAt
+		ac.raised.WithLabelValues(projectID, alert.ServiceID, strings.ToLower(alert.Type), strings.ToLower(alert.Severity)).Inc()
+	}
+}
+
+func (ac *alertsCollector) collectActive(ch chan<- prometheus.Metric, projectID string, alerts []shared.Alert) {
+	active := make(map[[3]string]int)
+	for _, alert := range alerts {
+		if !strings.EqualFold(alert.Status, "active") {
+			continue
+		}
+		active[[3]string{alert.ServiceID, strings.ToLower(alert.Type), strings.ToLower(alert.Severity)}]++
+	}
+
+	for key, count := range active {
+		ch <- prometheus.MustNewConstMetric(
+			ac.active,
+			prometheus.GaugeValue,
+			float64(count),
+			projectID,
+			key[0],
+			key[1],
+			key[2],
+		)
+	}
+}
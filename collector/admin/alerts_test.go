@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestAlertsCollector(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-prd", OrganizationId: "proj"}}
+
+	type poll struct {
+		windowed, active string
+	}
+	polls := []poll{
+		{
+			windowed: `[
+				{"id": "al1", "serviceId": "liferay", "type": "OUT_OF_MEMORY", "severity": "CRITICAL", "status": "ACTIVE", "createdAt": 1740916800000},
+				{"id": "al2", "serviceId": "liferay", "type": "OUT_OF_MEMORY", "severity": "CRITICAL", "status": "RESOLVED", "createdAt": 1740913200000, "resolvedAt": 1740914000000},
+				{"id": "al3", "serviceId": "search", "type": "DISK_PRESSURE", "severity": "WARNING", "status": "ACTIVE", "createdAt": 1740900000000},
+				{"id": "al0", "serviceId": "search", "type": "CRASH_LOOP", "severity": "CRITICAL", "status": "RESOLVED", "createdAt": 1740800000000, "resolvedAt": 1740800100000}
+			]`,
+			// al5 was raised before the window and is still active.
+			active: `[
+				{"id": "al1", "serviceId": "liferay", "type": "OUT_OF_MEMORY", "severity": "CRITICAL", "status": "ACTIVE", "createdAt": 1740916800000},
+				{"id": "al3", "serviceId": "search", "type": "DISK_PRESSURE", "severity": "WARNING", "status": "ACTIVE", "createdAt": 1740900000000},
+				{"id": "al5", "serviceId": "database", "type": "DISK_PRESSURE", "severity": "WARNING", "status": "ACTIVE", "createdAt": 1740700000000}
+			]`,
+		},
+		{
+			windowed: `[
+				{"id": "al1", "serviceId": "liferay", "type": "OUT_OF_MEMORY", "severity": "CRITICAL", "status": "RESOLVED", "createdAt": 1740916800000, "resolvedAt": 1740917000000},
+				{"id": "al3", "serviceId": "search", "type": "DISK_PRESSURE", "severity": "WARNING", "status": "ACTIVE", "createdAt": 1740900000000},
+				{"id": "al4", "serviceId": "webserver", "type": "CRASH_LOOP", "severity": "CRITICAL", "status": "ACTIVE", "createdAt": 1740920000000}
+			]`,
+			active: `[
+				{"id": "al3", "serviceId": "search", "type": "DISK_PRESSURE", "severity": "WARNING", "status": "ACTIVE", "createdAt": 1740900000000},
+				{"id": "al4", "serviceId": "webserver", "type": "CRASH_LOOP", "severity": "CRITICAL", "status": "ACTIVE", "createdAt": 1740920000000}
+			]`,
+		},
+	}
+
+	var current atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/projects/proj-prd/alerts", r.URL.Path)
+		query := r.URL.Query()
+		p := polls[current.Load()]
+
+		payload := p.windowed
+		if query.Has("start") {
+			require.Equal(t, "2025-03-02T00:00:00Z", query.Get("start"))
+		} else {
+			require.Equal(t, "active", query.Get("status"))
+			payload = p.active
+		}
+		_, err := fmt.Fprintln(w, payload)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 2, 18, 0, 0, 0, time.UTC)
+	window := internal.DateWindow{Mode: internal.DateWindowDay, Clock: func() time.Time { return now }}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAlertsCollector(client, projectProvider, window, 1)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	scrape := func() string {
+		resp, err := http.Get(serverMetrics.URL)
+		require.NoError(t, err)
+		defer func() {
+			closeErr := resp.Body.Close()
+			require.NoError(t, closeErr)
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	output := scrape()
+
+	require.Contains(t, output, `lcp_api_alerts_active{alert_type="out_of_memory",project="proj-prd",service="liferay",severity="critical"} 1`)
+	require.Contains(t, output, `lcp_api_alerts_active{alert_type="disk_pressure",project="proj-prd",service="search",severity="warning"} 1`)
+	require.Contains(t, output, `lcp_api_alerts_raised_total{alert_type="out_of_memory",project="proj-prd",service="liferay",severity="critical"} 2`)
+	require.Contains(t, output, `lcp_api_alerts_raised_total{alert_type="disk_pressure",project="proj-prd",service="search",severity="warning"} 1`)
+	require.Contains(t, output, `lcp_api_alerts_active{alert_type="disk_pressure",project="proj-prd",service="database",severity="warning"} 1`)
+	require.NotContains(t, output, `lcp_api_alerts_raised_total{alert_type="disk_pressure",project="proj-prd",service="database"`)
+	require.NotContains(t, output, `alert_type="crash_loop"`)
+
+	current.Store(1)
+	output = scrape()
+
+	require.NotContains(t, output, `lcp_api_alerts_active{alert_type="out_of_memory"`)
+	require.NotContains(t, output, `lcp_api_alerts_active{alert_type="disk_pressure",project="proj-prd",service="database"`)
+	require.Contains(t, output, `lcp_api_alerts_active{alert_type="crash_loop",project="proj-prd",service="webserver",severity="critical"} 1`)
+	require.Contains(t, output, `lcp_api_alerts_raised_total{alert_type="out_of_memory",project="proj-prd",service="liferay",severity="critical"} 2`)
+	require.Contains(t, output, `lcp_api_alerts_raised_total{alert_type="disk_pressure",project="proj-prd",service="search",severity="warning"} 1`)
+	require.Contains(t, output, `lcp_api_alerts_raised_total{alert_type="crash_loop",project="proj-prd",service="webserver",severity="critical"} 1`)
+}
@@ -1,35 +1,109 @@
 package admin
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/jullianow/lcp-exporter/config"
 	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/events"
 	"github.com/jullianow/lcp-exporter/internal/shared"
 	"github.com/jullianow/lcp-exporter/lcp"
 )
 
+type AutoscaleOptions struct {
+	Window        internal.DateWindow
+	BillingPeriod bool
+	Budgets       *config.Budgets
+	LegacyHistory bool
+	Events        *events.Dispatcher
+	BatchSize     int
+	Concurrency   int
+	ExchangeRates *config.ExchangeRatesFile
+}
+
 type autoscaleCollector struct {
 	client          *lcp.Client
 	projectProvider ProjectProvider
-	dataRange       shared.DateRange
+	options         AutoscaleOptions
+
+	mu         sync.Mutex
+	seenEvents map[string]int64
+
+	scaleLimitChanges      *prometheus.CounterVec
+	scalingEvents          *prometheus.CounterVec
+	scalingEventDuration   *prometheus.HistogramVec
+	scalingEventInstances  *prometheus.HistogramVec
+	scalingInstanceSeconds *prometheus.CounterVec
 
-	activationHistory        *prometheus.Desc
-	billableDurationMs       *prometheus.Desc
-	costAmount               *prometheus.Desc
-	scalingHistoryDurationMs *prometheus.Desc
-	priceAmount              *prometheus.Desc
-	totalCostDurationMs      *prometheus.Desc
+	activationHistory         *prometheus.Desc
+	billableDurationMs        *prometheus.Desc
+	billingBillableDurationMs *prometheus.Desc
+	billingCostAmount         *prometheus.Desc
+	budgetAmount              *prometheus.Desc
+	budgetUtilizationRatio    *prometheus.Desc
+	costAmount                *prometheus.Desc
+	costForecastAmount        *prometheus.Desc
+	costNormalizedAmount      *prometheus.Desc
+	enabled                   *prometheus.Desc
+	maxInstances              *prometheus.Desc
+	minInstances              *prometheus.Desc
+	missingSubtotal           *prometheus.Desc
+	orphanSubtotal            *prometheus.Desc
+	scalingHistoryDurationMs  *prometheus.Desc
+	priceAmount               *prometheus.Desc
+	totalCostDurationMs       *prometheus.Desc
 }
 
-func NewAutoscaleCollector(client *lcp.Client, provider ProjectProvider, dataRange shared.DateRange) *autoscaleCollector {
+func NewAutoscaleCollector(client *lcp.Client, provider ProjectProvider, options AutoscaleOptions) *autoscaleCollector {
 	fqName := internal.Name("autoscale")
 
 	return &autoscaleCollector{
 		client:          client,
 		projectProvider: provider,
-		dataRange:       dataRange,
+		options:         options,
+		seenEvents:      make(map[string]int64),
+		scaleLimitChanges: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fqName("scale_limit_changes_total"),
+				Help: "Total number of autoscale min/max instance limit changes by project and service",
+			},
+			[]string{"project_name", "service_id"},
+		),
+		scalingEvents: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fqName("scaling_events_total"),
+				Help: "Total number of finished scaling events by project and service",
+			},
+			[]string{"project_name", "service_id"},
+		),
+		scalingEventDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    fqName("scaling_event_duration_seconds"),
+				Help:    "Duration of finished scaling events in seconds by project and service",
+				Buckets: prometheus.ExponentialBuckets(60, 2, 10),
+			},
+			[]string{"project_name", "service_id"},
+		),
+		scalingEventInstances: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    fqName("scaling_event_additional_instances"),
+				Help:    "Additional instances of finished scaling events by project and service",
+				Buckets: prometheus.LinearBuckets(1, 1, 10),
+			},
+			[]string{"project_name", "service_id"},
+		),
+		scalingInstanceSeconds: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fqName("scaling_instance_seconds_total"),
+				Help: "Total additional instance-seconds of finished scaling events by project and service",
+			},
+			[]string{"project_name", "service_id"},
+		),
 		activationHistory: prometheus.NewDesc(
 			fqName("activation_history_count"),
 			"History total instances activated by project and service",
@@ -42,12 +116,78 @@ func NewAutoscaleCollector(client *lcp.Client, provider ProjectProvider, dataRan
 			[]string{"project_name"},
 			nil,
 		),
+		billingBillableDurationMs: prometheus.NewDesc(
+			fqName("billing_billable_duration_ms"),
+			"Billable time by project and billing period in milliseconds",
+			[]string{"project_name", "period"},
+			nil,
+		),
+		billingCostAmount: prometheus.NewDesc(
+			fqName("billing_cost_amount"),
+			"Cost of the autoscale by project and billing period",
+			[]string{"project_name", "currency_code", "period"},
+			nil,
+		),
+		budgetAmount: prometheus.NewDesc(
+			fqName("budget_amount"),
+			"Monthly autoscale budget by project",
+			[]string{"project_name", "currency_code"},
+			nil,
+		),
+		budgetUtilizationRatio: prometheus.NewDesc(
+			fqName("budget_utilization_ratio"),
+			"Month-to-date autoscale cost divided by the monthly budget by project",
+			[]string{"project_name"},
+			nil,
+		),
 		costAmount: prometheus.NewDesc(
 			fqName("cost_amount"),
 			"Cost of the autoscale by project",
 			[]string{"project_name", "currency_code"},
 			nil,
 		),
+		costNormalizedAmount: prometheus.NewDesc(
+			fqName("cost_normalized_amount"),
+			"Cost of the autoscale by project converted to the target currency of the exchange rates",
+			[]string{"project_name", "currency_code"},
+			nil,
+		),
+		costForecastAmount: prometheus.NewDesc(
+			fqName("cost_forecast_amount"),
+			"Linear forecast of the autoscale cost at the end of the current month by project",
+			[]string{"project_name", "currency_code"},
+			nil,
+		),
+		enabled: prometheus.NewDesc(
+			fqName("enabled"),
+			"1 if autoscale is currently enabled for the service, 0 otherwise",
+			[]string{"project_name", "service_id"},
+			nil,
+		),
+		maxInstances: prometheus.NewDesc(
+			fqName("max_instances"),
+			"Current maximum number of autoscale instances by project and service",
+			[]string{"project_name", "service_id"},
+			nil,
+		),
+		minInstances: prometheus.NewDesc(
+			fqName("min_instances"),
+			"Current minimum number of autoscale instances by project and service",
+			[]string{"project_name", "service_id"},
+			nil,
+		),
+		missingSubtotal: prometheus.NewDesc(
+			fqName("missing_subtotal"),
+			"1 if the project is included in the autoscale report but has no subtotal, 0 otherwise",
+			[]string{"project_name"},
+			nil,
+		),
+		orphanSubtotal: prometheus.NewDesc(
+			fqName("orphan_subtotal"),
+			"1 if the project has an autoscale subtotal but is not included in the report, 0 otherwise",
+			[]string{"project_name"},
+			nil,
+		),
 		scalingHistoryDurationMs: prometheus.NewDesc(
 			fqName("scaling_history_duration_ms"),
 			"History scaling time in milliseconds by project and service",
@@ -72,10 +212,26 @@ func NewAutoscaleCollector(client *lcp.Client, provider ProjectProvider, dataRan
 func (ac *autoscaleCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- ac.activationHistory
 	ch <- ac.billableDurationMs
+	ch <- ac.billingBillableDurationMs
+	ch <- ac.billingCostAmount
+	ch <- ac.budgetAmount
+	ch <- ac.budgetUtilizationRatio
 	ch <- ac.costAmount
+	ch <- ac.costForecastAmount
+	ch <- ac.costNormalizedAmount
+	ch <- ac.enabled
+	ch <- ac.maxInstances
+	ch <- ac.minInstances
+	ch <- ac.missingSubtotal
+	ch <- ac.orphanSubtotal
 	ch <- ac.priceAmount
 	ch <- ac.scalingHistoryDurationMs
 	ch <- ac.totalCostDurationMs
+	ac.scaleLimitChanges.Describe(ch)
+	ac.scalingEvents.Describe(ch)
+	ac.scalingEventDuration.Describe(ch)
+	ac.scalingEventInstances.Describe(ch)
+	ac.scalingInstanceSeconds.Describe(ch)
 }
 
 func (ac *autoscaleCollector) Collect(ch chan<- prometheus.Metric) {
@@ -83,85 +239,133 @@ func (ac *autoscaleCollector) Collect(ch chan<- prometheus.Metric) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		ac.collectMetrics(ch)
+		_ = ac.Update(context.Background(), ch)
 	}()
 	wg.Wait()
 }
 
-func (ac *autoscaleCollector) collectMetrics(ch chan<- prometheus.Metric) {
-	projects := ac.projectProvider.GetProjects()
+func (ac *autoscaleCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := ac.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
 
 	if len(projects) == 0 {
 		internal.LogWarn("AutoscaleCollector", "No projects found")
-		return
+		return nil
 	}
 
 	internal.LogDebug("AutoscaleCollector", "Found %d projects", len(projects))
 
-	queryParams := map[string]string{
-		"start":      ac.dataRange.From,
-		"end":        ac.dataRange.End,
-		"projectIds": internal.JoinStrings(internal.GetRootProjectIDs(projects), ","),
+	rootProjectIDs := internal.GetRootProjectIDs(projects)
+	rootProjectNames := make(map[string]string, len(projects))
+	for _, project := range projects {
+		rootProjectNames[project.ProjectID] = internal.RootProjectName(project)
 	}
 
-	internal.LogDebug(
-		"AutoscaleCollector",
-		"Fetched autoscale data for projects: %s | start: %s | end: %s",
-		queryParams["projectIds"],
-		queryParams["start"],
-		queryParams["end"],
-	)
+	dataRange := ac.options.Window.Range()
+	stat, err := ac.fetchStats(ctx, rootProjectIDs, dataRange)
+	if err != nil {
+		return err
+	}
 
-	stats, err := lcp.FetchFrom[shared.Autoscale](ac.client, "/admin/reports/autoscale/stats", queryParams)
+	ac.publishEvents(ctx, stat, dataRange)
 
-	if err != nil {
-		internal.LogError("AutoscaleCollector", "Failed to fetch autoscale overview: %v", err)
-		return
+	includedChildProjectIds := make(map[string]struct{}, len(stat.IncludedChildProjectIds))
+	for _, childProjectId := range stat.IncludedChildProjectIds {
+		includedChildProjectIds[childProjectId] = struct{}{}
+
+		_, ok := stat.SubtotalsByProjectId[childProjectId]
+		if !ok {
+			internal.LogWarn("AutoscaleCollector", "Project %s is included but has no subtotal", childProjectId)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.missingSubtotal,
+			prometheus.GaugeValue,
+			internal.BoolToFloat(!ok),
+			childProjectId,
+		)
+	}
+
+	for projectId, subtotal := range stat.SubtotalsByProjectId {
+		_, ok := includedChildProjectIds[projectId]
+		if !ok {
+			internal.LogWarn("AutoscaleCollector", "Project %s has a subtotal but is not included", projectId)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.orphanSubtotal,
+			prometheus.GaugeValue,
+			internal.BoolToFloat(!ok),
+			projectId,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.billableDurationMs,
+			prometheus.GaugeValue,
+			float64(subtotal.BillableTimeMs),
+			projectId,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.costAmount,
+			prometheus.GaugeValue,
+			float64(subtotal.Cost.Amount),
+			projectId,
+			subtotal.Cost.Currency,
+		)
+
+		ac.collectNormalizedCost(ch, projectId, subtotal.Cost)
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.priceAmount,
+			prometheus.GaugeValue,
+			float64(subtotal.Price.Amount),
+			projectId,
+			subtotal.Price.Currency,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.totalCostDurationMs,
+			prometheus.GaugeValue,
+			float64(subtotal.TotalActiveTimeMs),
+			projectId,
+		)
 	}
 
-	stat := stats[0]
-	childProjectIds := stat.IncludedChildProjectIds
-	subtotalsByProjectIds := stat.SubtotalsByProjectId
-	totalChildProjectIds := len(childProjectIds)
-	totalSubtotalsByProjectIds := len(subtotalsByProjectIds)
+	internal.LogDebug("AutoscaleCollector", "Found %d scaling history events", len(stat.ScalingHistory))
+	ac.collectScalingHistory(ch, stat.ScalingHistory, dataRange)
 
-	if (totalChildProjectIds+totalSubtotalsByProjectIds)%2 == 0 && (totalChildProjectIds+totalSubtotalsByProjectIds) > 0 {
-		for _, childProjectId := range childProjectIds {
-			subtotalsByProjectId := subtotalsByProjectIds[childProjectId]
+	internal.LogDebug("AutoscaleCollector", "Found %d activation history events", len(stat.ActivationHistory))
+	ac.collectActivationHistory(ch, stat.ActivationHistory, dataRange)
 
-			ch <- prometheus.MustNewConstMetric(
-				ac.billableDurationMs,
-				prometheus.GaugeValue,
-				float64(subtotalsByProjectId.BillableTimeMs),
-				childProjectId,
-			)
+	if !ac.options.BillingPeriod && ac.options.Budgets == nil {
+		return nil
+	}
 
-			ch <- prometheus.MustNewConstMetric(
-				ac.costAmount,
-				prometheus.GaugeValue,
-				float64(subtotalsByProjectId.Cost.Amount),
-				childProjectId,
-				subtotalsByProjectId.Cost.Currency,
-			)
+	// Budgets are monthly, so they are compared against the month-to-date cost
+	// whatever the report window is.
+	now := ac.options.Window.Now()
+	current, err := ac.fetchStats(ctx, rootProjectIDs, internal.MonthToDate(now))
+	if err != nil {
+		return err
+	}
 
-			ch <- prometheus.MustNewConstMetric(
-				ac.priceAmount,
-				prometheus.GaugeValue,
-				float64(subtotalsByProjectId.Price.Amount),
-				childProjectId,
-				subtotalsByProjectId.Price.Currency,
-			)
+	if ac.options.Budgets != nil {
+		ac.collectBudgets(ch, current, rootProjectIDs, rootProjectNames)
+	}
 
-			ch <- prometheus.MustNewConstMetric(
-				ac.totalCostDurationMs,
-				prometheus.GaugeValue,
-				float64(subtotalsByProjectId.TotalActiveTimeMs),
-				childProjectId,
-			)
-		}
+	if ac.options.BillingPeriod {
+		return ac.collectBillingPeriod(ctx, ch, rootProjectIDs, now, current)
+	}
+
+	return nil
+}
 
-		internal.LogDebug("AutoscaleCollector", "Found %d scaling history events", len(stat.ScalingHistory))
-		for _, event := range stat.ScalingHistory {
+func (ac *autoscaleCollector) collectScalingHistory(ch chan<- prometheus.Metric, events []shared.AutoscaleScalingHistory, dataRange shared.DateRange) {
+	if ac.options.LegacyHistory {
+		for _, event := range events {
 			ch <- prometheus.MustNewConstMetric(
 				ac.scalingHistoryDurationMs,
 				prometheus.GaugeValue,
@@ -173,9 +377,57 @@ func (ac *autoscaleCollector) collectMetrics(ch chan<- prometheus.Metric) {
 				internal.IntToString(event.NumAdditionalInstances),
 			)
 		}
+	}
+
+	from := internal.DateRangeStartMillis(dataRange)
 
-		internal.LogDebug("AutoscaleCollector", "Found %d activation history events", len(stat.ActivationHistory))
-		for _, event := range stat.ActivationHistory {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	for _, event := range events {
+		if event.EndedAt == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("scaling/%s/%s/%d", event.ProjectID, event.ServiceID, event.StartedAt)
+		if !ac.firstSeen(key, event.StartedAt, from) {
+			continue
+		}
+
+		ac.scalingEvents.WithLabelValues(event.ProjectID, event.ServiceID).Inc()
+		ac.scalingEventDuration.WithLabelValues(event.ProjectID, event.ServiceID).Observe(internal.MillisToSeconds(event.EndedAt - event.StartedAt))
+		ac.scalingEventInstances.WithLabelValues(event.ProjectID, event.ServiceID).Observe(float64(event.NumAdditionalInstances))
+		ac.scalingInstanceSeconds.WithLabelValues(event.ProjectID, event.ServiceID).Add(
+			internal.MillisToSeconds(event.ActiveTimePerInstanceMs) * float64(event.NumAdditionalInstances),
+		)
+	}
+
+	ac.scalingEvents.Collect(ch)
+	ac.scalingEventDuration.Collect(ch)
+	ac.scalingEventInstances.Collect(ch)
+	ac.scalingInstanceSeconds.Collect(ch)
+}
+
+func (ac *autoscaleCollector) collectActivationHistory(ch chan<- prometheus.Metric, events []shared.AutoscaleActivationHistory, dataRange shared.DateRange) {
+	from := internal.DateRangeStartMillis(dataRange)
+	latest := make(map[[2]string]shared.AutoscaleActivationHistory)
+
+	ac.mu.Lock()
+	for _, event := range events {
+		for _, change := range event.ScaleLimitsChanges {
+			key := fmt.Sprintf("scale_limits/%s/%s/%d", event.ProjectID, event.ServiceID, change.ChangedAt)
+			if ac.firstSeen(key, change.ChangedAt, from) {
+				ac.scaleLimitChanges.WithLabelValues(event.ProjectID, event.ServiceID).Inc()
+			}
+		}
+	}
+	ac.pruneSeen(from)
+	ac.mu.Unlock()
+
+	ac.scaleLimitChanges.Collect(ch)
+
+	for _, event := range events {
+		if ac.options.LegacyHistory {
 			numInstances := 0.0
 			if event.DisabledAt > 0 {
 				numInstances = float64(event.MaxInstances)
@@ -193,12 +445,328 @@ func (ac *autoscaleCollector) collectMetrics(ch chan<- prometheus.Metric) {
 			)
 		}
 
-	} else {
-		internal.LogWarn(
-			"AutoscaleCollector",
-			"includedChildProjectIds=%d and subtotalsByProjectIds=%d do not match",
-			len(childProjectIds),
-			len(subtotalsByProjectIds),
+		key := [2]string{event.ProjectID, event.ServiceID}
+		if current, ok := latest[key]; !ok || event.EnabledAt > current.EnabledAt {
+			latest[key] = event
+		}
+	}
+
+	for key, event := range latest {
+		var enabled float64
+		if event.DisabledAt == 0 {
+			enabled = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.enabled,
+			prometheus.GaugeValue,
+			enabled,
+			key[0],
+			key[1],
+		)
+
+		if event.DisabledAt > 0 {
+			continue
+		}
+
+		minInstances, maxInstances := internal.CurrentScaleLimits(event)
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.minInstances,
+			prometheus.GaugeValue,
+			float64(minInstances),
+			key[0],
+			key[1],
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			ac.maxInstances,
+			prometheus.GaugeValue,
+			float64(maxInstances),
+			key[0],
+			key[1],
 		)
 	}
 }
+
+func (ac *autoscaleCollector) firstSeen(key string, at, from int64) bool {
+	if at < from {
+		return false
+	}
+	if _, seen := ac.seenEvents[key]; seen {
+		return false
+	}
+	ac.seenEvents[key] = at
+	return true
+}
+
+func (ac *autoscaleCollector) pruneSeen(from int64) {
+	for key, at := range ac.seenEvents {
+		if at < from {
+			delete(ac.seenEvents, key)
+		}
+	}
+}
+
+func (ac *autoscaleCollector) publishEvents(ctx context.Context, stat *shared.Autoscale, dataRange shared.DateRange) {
+	if ac.options.Events == nil {
+		return
+	}
+
+	var batch []events.Event
+	for _, event := range stat.ScalingHistory {
+		if event.EndedAt == 0 {
+			continue
+		}
+		batch = append(batch, events.Event{
+			ID:        fmt.Sprintf("autoscale_scaling/%s/%s/%d", event.ProjectID, event.ServiceID, event.StartedAt),
+			Kind:      "autoscale_scaling",
+			ProjectID: event.ProjectID,
+			ServiceID: event.ServiceID,
+			Timestamp: time.UnixMilli(event.EndedAt).UTC(),
+			Payload:   event,
+		})
+	}
+
+	for _, event := range stat.ActivationHistory {
+		batch = append(batch, events.Event{
+			ID:        fmt.Sprintf("autoscale_enabled/%s/%s/%d", event.ProjectID, event.ServiceID, event.EnabledAt),
+			Kind:      "autoscale_enabled",
+			ProjectID: event.ProjectID,
+			ServiceID: event.ServiceID,
+			Timestamp: time.UnixMilli(event.EnabledAt).UTC(),
+			Payload:   event,
+		})
+
+		if event.DisabledAt > 0 {
+			batch = append(batch, events.Event{
+				ID:        fmt.Sprintf("autoscale_disabled/%s/%s/%d", event.ProjectID, event.ServiceID, event.DisabledAt),
+				Kind:      "autoscale_disabled",
+				ProjectID: event.ProjectID,
+				ServiceID: event.ServiceID,
+				Timestamp: time.UnixMilli(event.DisabledAt).UTC(),
+				Payload:   event,
+			})
+		}
+	}
+
+	ac.options.Events.Publish(ctx, batch)
+
+	if from, err := time.Parse(time.RFC3339, dataRange.From); err == nil {
+		ac.options.Events.Prune(from)
+	}
+}
+
+func (ac *autoscaleCollector) collectNormalizedCost(ch chan<- prometheus.Metric, projectID string, cost shared.AutoscaleCost) {
+	if ac.options.ExchangeRates == nil {
+		return
+	}
+
+	rates := ac.options.ExchangeRates.Rates()
+	amount, ok := rates.Convert(cost.Amount, cost.Currency)
+	if !ok {
+		internal.LogWarn("AutoscaleCollector", "No exchange rate from %s to %s for project %s", cost.Currency, rates.Target, projectID)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		ac.costNormalizedAmount,
+		prometheus.GaugeValue,
+		amount,
+		projectID,
+		rates.Target,
+	)
+}
+
+// collectBudgets compares the budget of each root project with the summed
+// cost of its children, and the budget of a child project with an explicit
+// entry with its own cost.
+func (ac *autoscaleCollector) collectBudgets(ch chan<- prometheus.Metric, current *shared.Autoscale, rootProjectIDs []string, rootProjectNames map[string]string) {
+	roots := make(map[string]struct{}, len(rootProjectIDs))
+	costs := make(map[string]shared.AutoscaleCost, len(rootProjectIDs))
+	for _, rootProjectID := range rootProjectIDs {
+		roots[rootProjectID] = struct{}{}
+		costs[rootProjectID] = shared.AutoscaleCost{}
+	}
+
+	for projectID, subtotal := range current.SubtotalsByProjectId {
+		rootProjectID := rootProjectNames[projectID]
+		if rootProjectID == "" {
+			rootProjectID = projectID
+		}
+
+		if _, ok := ac.options.Budgets.Projects[projectID]; ok && rootProjectID != projectID {
+			costs[projectID] = subtotal.Cost
+		}
+
+		cost := costs[rootProjectID]
+		if cost.Currency != "" && cost.Currency != subtotal.Cost.Currency {
+			internal.LogWarn("AutoscaleCollector", "Skipping cost of project %s in the budget of %s: currency %s does not match %s", projectID, rootProjectID, subtotal.Cost.Currency, cost.Currency)
+			continue
+		}
+		cost.Amount += subtotal.Cost.Amount
+		cost.Currency = subtotal.Cost.Currency
+		costs[rootProjectID] = cost
+	}
+
+	for projectID, cost := range costs {
+		_, root := roots[projectID]
+		ac.collectBudget(ch, projectID, root, cost)
+	}
+}
+
+func (ac *autoscaleCollector) collectBudget(ch chan<- prometheus.Metric, projectID string, root bool, cost shared.AutoscaleCost) {
+	budget, ok := ac.options.Budgets.For(projectID, root)
+	if !ok {
+		return
+	}
+
+	currency := ac.options.Budgets.Currency
+	if currency == "" {
+		currency = cost.Currency
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		ac.budgetAmount,
+		prometheus.GaugeValue,
+		budget,
+		projectID,
+		currency,
+	)
+
+	if cost.Currency != "" && cost.Currency != currency {
+		internal.LogWarn("AutoscaleCollector", "Budget currency %s does not match cost currency %s for project %s", currency, cost.Currency, projectID)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		ac.budgetUtilizationRatio,
+		prometheus.GaugeValue,
+		cost.Amount/budget,
+		projectID,
+	)
+}
+
+func (ac *autoscaleCollector) fetchStats(ctx context.Context, projectIDs []string, dataRange shared.DateRange) (*shared.Autoscale, error) {
+	batches := internal.ChunkStrings(projectIDs, ac.options.BatchSize)
+	if len(batches) == 0 {
+		batches = [][]string{nil}
+	}
+
+	concurrency := max(ac.options.Concurrency, 1)
+	results := make([]shared.Autoscale, len(batches))
+	errs := make([]error, len(batches))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stat, err := ac.fetchBatch(ctx, batch, dataRange)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i] = *stat
+		}(i, batch)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("batch %d/%d: %w", i+1, len(batches), err)
+		}
+	}
+
+	internal.LogDebug("AutoscaleCollector", "Merged autoscale data from %d batches", len(batches))
+	merged := internal.FilterAutoscale(internal.MergeAutoscale(results), func(projectID string) bool {
+		return !ac.projectProvider.Excluded(projectID)
+	})
+	return &merged, nil
+}
+
+func (ac *autoscaleCollector) fetchBatch(ctx context.Context, projectIDs []string, dataRange shared.DateRange) (*shared.Autoscale, error) {
+	queryParams := map[string]string{
+		"start":      dataRange.From,
+		"end":        dataRange.End,
+		"projectIds": internal.JoinStrings(projectIDs, ","),
+	}
+
+	internal.LogDebug(
+		"AutoscaleCollector",
+		"Fetched autoscale data for projects: %s | start: %s | end: %s",
+		queryParams["projectIds"],
+		queryParams["start"],
+		queryParams["end"],
+	)
+
+	stats, err := lcp.FetchFrom[shared.Autoscale](ctx, ac.client, "/admin/reports/autoscale/stats", queryParams)
+
+	if err != nil {
+		internal.LogError("AutoscaleCollector", "Failed to fetch autoscale overview: %v", err)
+		return nil, err
+	}
+
+	if len(stats) == 0 {
+		internal.LogWarn("AutoscaleCollector", "No autoscale data returned")
+		return nil, fmt.Errorf("no autoscale data returned")
+	}
+
+	return &stats[0], nil
+}
+
+func (ac *autoscaleCollector) collectBillingPeriod(ctx context.Context, ch chan<- prometheus.Metric, projectIDs []string, now time.Time, current *shared.Autoscale) error {
+	previous, err := ac.fetchStats(ctx, projectIDs, internal.PreviousMonth(now))
+	if err != nil {
+		return err
+	}
+
+	periods := []struct {
+		name string
+		stat *shared.Autoscale
+	}{
+		{name: "current", stat: current},
+		{name: "previous", stat: previous},
+	}
+
+	for _, period := range periods {
+		for projectID, subtotal := range period.stat.SubtotalsByProjectId {
+			ch <- prometheus.MustNewConstMetric(
+				ac.billingBillableDurationMs,
+				prometheus.GaugeValue,
+				float64(subtotal.BillableTimeMs),
+				projectID,
+				period.name,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				ac.billingCostAmount,
+				prometheus.GaugeValue,
+				subtotal.Cost.Amount,
+				projectID,
+				subtotal.Cost.Currency,
+				period.name,
+			)
+
+			if period.name != "current" {
+				continue
+			}
+
+			if progress := internal.MonthProgress(now); progress > 0 {
+				ch <- prometheus.MustNewConstMetric(
+					ac.costForecastAmount,
+					prometheus.GaugeValue,
+					subtotal.Cost.Amount/progress,
+					projectID,
+					subtotal.Cost.Currency,
+				)
+			}
+		}
+	}
+
+	return nil
+}
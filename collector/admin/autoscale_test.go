@@ -1,10 +1,18 @@
 package admin
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,52 +20,52 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jullianow/lcp-exporter/config"
 	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/events"
 	"github.com/jullianow/lcp-exporter/internal/shared"
 	"github.com/jullianow/lcp-exporter/lcp"
 )
 
 func TestAutoscaleCollector(t *testing.T) {
-	projectProvider := &ProjectsCollector{
-		projects: []shared.Projects{
-			{
-				ProjectID: "proj-1",
-				Cluster:   "project-123_cluster-1",
-				Metadata: shared.ProjectMetadata{
-					Subscription: struct {
-						Availability string `json:"availability"`
-						EnvType      string `json:"envType"`
-					}{
-						Availability: "HA",
-						EnvType:      "prod",
-					},
-					Commerce:    true,
-					DocLibStore: "doclib",
-					Trial:       "false",
+	projectProvider := staticProjects{
+		{
+			ProjectID: "proj-1",
+			Cluster:   "project-123_cluster-1",
+			Metadata: shared.ProjectMetadata{
+				Subscription: struct {
+					Availability string `json:"availability"`
+					EnvType      string `json:"envType"`
+				}{
+					Availability: "HA",
+					EnvType:      "prod",
 				},
-				CreatedAt: 1740926966862,
-				Status:    "running",
-				Health:    "healthy",
+				Commerce:    true,
+				DocLibStore: "doclib",
+				Trial:       "false",
 			},
-			{
-				ProjectID: "proj-2",
-				Cluster:   "project-123_cluster-1",
-				Metadata: shared.ProjectMetadata{
-					Subscription: struct {
-						Availability string `json:"availability"`
-						EnvType      string `json:"envType"`
-					}{
-						Availability: "HA",
-						EnvType:      "prod",
-					},
-					Commerce:    true,
-					DocLibStore: "doclib",
-					Trial:       "false",
+			CreatedAt: 1740926966862,
+			Status:    "running",
+			Health:    "healthy",
+		},
+		{
+			ProjectID: "proj-2",
+			Cluster:   "project-123_cluster-1",
+			Metadata: shared.ProjectMetadata{
+				Subscription: struct {
+					Availability string `json:"availability"`
+					EnvType      string `json:"envType"`
+				}{
+					Availability: "HA",
+					EnvType:      "prod",
 				},
-				CreatedAt: 1740926966862,
-				Status:    "running",
-				Health:    "healthy",
+				Commerce:    true,
+				DocLibStore: "doclib",
+				Trial:       "false",
 			},
+			CreatedAt: 1740926966862,
+			Status:    "running",
+			Health:    "healthy",
 		},
 	}
 
@@ -144,7 +152,10 @@ func TestAutoscaleCollector(t *testing.T) {
 		}
 	}`
 
-	dataRange := internal.CalculateDates(1 * time.Hour)
+	options := AutoscaleOptions{
+		Window:        internal.DateWindow{Mode: internal.DateWindowLast, Duration: 1 * time.Hour},
+		LegacyHistory: true,
+	}
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		require.Equal(t, "/admin/reports/autoscale/stats", r.URL.Path)
@@ -154,7 +165,7 @@ func TestAutoscaleCollector(t *testing.T) {
 	defer server.Close()
 
 	client := lcp.NewClient(server.URL, "fake-token")
-	collector := NewAutoscaleCollector(client, projectProvider, dataRange)
+	collector := NewAutoscaleCollector(client, projectProvider, options)
 
 	reg := prometheus.NewRegistry()
 	require.NoError(t, reg.Register(collector))
@@ -187,3 +198,633 @@ func TestAutoscaleCollector(t *testing.T) {
 	require.Contains(t, output, `lcp_api_autoscale_scaling_history_duration_ms{ended_at="1740927226281",instances="1",project_name="proj-1",service_id="liferay",started_at="1740927029636"} 196645`)
 	require.Contains(t, output, `lcp_api_autoscale_scaling_history_duration_ms{ended_at="1740927803626",instances="2",project_name="proj-2",service_id="liferay",started_at="1740927736767"} 66859`)
 }
+
+func TestAutoscaleCollector_RecomputesWindowOnEveryCollection(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-1", OrganizationId: "proj-1"}}
+
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		starts = append(starts, r.URL.Query().Get("start"))
+		_, err := fmt.Fprintln(w, `{"includedChildProjectIds": [], "subtotalsByProjectId": {}}`)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	options := AutoscaleOptions{
+		Window: internal.DateWindow{Mode: internal.DateWindowMonth, Clock: func() time.Time { return now }},
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	_, err := reg.Gather()
+	require.NoError(t, err)
+
+	now = now.Add(24 * time.Hour)
+	_, err = reg.Gather()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"2025-03-01T00:00:00Z", "2025-04-01T00:00:00Z"}, starts)
+}
+
+func TestAutoscaleCollector_BillingPeriod(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-1", OrganizationId: "proj-1"}}
+
+	responses := map[string]string{
+		"2025-04-01T00:00:00Z": `{"includedChildProjectIds": ["proj-1"], "subtotalsByProjectId": {"proj-1": {"billableTimeMs": 3600000, "cost": {"amount": 10, "currency": "USD"}}}}`,
+		"2025-03-01T00:00:00Z": `{"includedChildProjectIds": ["proj-1"], "subtotalsByProjectId": {"proj-1": {"billableTimeMs": 7200000, "cost": {"amount": 25, "currency": "USD"}}}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/admin/reports/autoscale/stats", r.URL.Path)
+		body, ok := responses[r.URL.Query().Get("start")]
+		if !ok {
+			body = `{"includedChildProjectIds": [], "subtotalsByProjectId": {}}`
+		}
+		_, err := fmt.Fprintln(w, body)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC)
+	options := AutoscaleOptions{
+		Window:        internal.DateWindow{Mode: internal.DateWindowDay, Clock: func() time.Time { return now }},
+		BillingPeriod: true,
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	require.Contains(t, output, `lcp_api_autoscale_billing_cost_amount{currency_code="USD",period="current",project_name="proj-1"} 10`)
+	require.Contains(t, output, `lcp_api_autoscale_billing_cost_amount{currency_code="USD",period="previous",project_name="proj-1"} 25`)
+	require.Contains(t, output, `lcp_api_autoscale_billing_billable_duration_ms{period="current",project_name="proj-1"} 3.6e+06`)
+	require.Contains(t, output, `lcp_api_autoscale_billing_billable_duration_ms{period="previous",project_name="proj-1"} 7.2e+06`)
+	require.Contains(t, output, `lcp_api_autoscale_cost_forecast_amount{currency_code="USD",project_name="proj-1"} 20`)
+}
+
+func TestAutoscaleCollector_Budgets(t *testing.T) {
+	projectProvider := staticProjects{
+		{ProjectID: "proj", OrganizationId: "proj"},
+		{ProjectID: "proj-prd", OrganizationId: "proj"},
+		{ProjectID: "proj-uat", OrganizationId: "proj"},
+		{ProjectID: "proj-dev", OrganizationId: "proj"},
+		{ProjectID: "acme-prd", OrganizationId: "acme"},
+		{ProjectID: "acme-dev", OrganizationId: "acme"},
+	}
+
+	responses := map[string]string{
+		"2025-04-16T00:00:00Z": `{
+			"includedChildProjectIds": ["proj-prd", "proj-uat", "proj-dev", "acme-prd", "acme-dev"],
+			"subtotalsByProjectId": {
+				"proj-prd": {"cost": {"amount": 2, "currency": "USD"}},
+				"proj-uat": {"cost": {"amount": 1, "currency": "USD"}},
+				"proj-dev": {"cost": {"amount": 1, "currency": "USD"}},
+				"acme-prd": {"cost": {"amount": 1, "currency": "USD"}},
+				"acme-dev": {"cost": {"amount": 1, "currency": "USD"}}
+			}
+		}`,
+		"2025-04-01T00:00:00Z": `{
+			"includedChildProjectIds": ["proj-prd", "proj-uat", "proj-dev", "acme-prd", "acme-dev"],
+			"subtotalsByProjectId": {
+				"proj-prd": {"cost": {"amount": 40, "currency": "USD"}},
+				"proj-uat": {"cost": {"amount": 15, "currency": "USD"}},
+				"proj-dev": {"cost": {"amount": 5, "currency": "USD"}},
+				"acme-prd": {"cost": {"amount": 60, "currency": "USD"}},
+				"acme-dev": {"cost": {"amount": 30, "currency": "USD"}}
+			}
+		}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Query().Get("start")]
+		require.True(t, ok, "unexpected start %s", r.URL.Query().Get("start"))
+		_, err := fmt.Fprintln(w, body)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 4, 16, 12, 0, 0, 0, time.UTC)
+	options := AutoscaleOptions{
+		Window: internal.DateWindow{Mode: internal.DateWindowDay, Clock: func() time.Time { return now }},
+		Budgets: &config.Budgets{
+			Default:  10,
+			Projects: map[string]float64{"proj-prd": 50, "proj-uat": 0, "acme": 100},
+		},
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	require.Contains(t, output, `lcp_api_autoscale_cost_amount{currency_code="USD",project_name="proj-prd"} 2`)
+	require.Contains(t, output, `lcp_api_autoscale_budget_amount{currency_code="USD",project_name="proj-prd"} 50`)
+	require.Contains(t, output, `lcp_api_autoscale_budget_utilization_ratio{project_name="proj-prd"} 0.8`)
+	require.Contains(t, output, `lcp_api_autoscale_budget_amount{currency_code="USD",project_name="proj"} 10`)
+	require.Contains(t, output, `lcp_api_autoscale_budget_utilization_ratio{project_name="proj"} 6`)
+	require.NotContains(t, output, `lcp_api_autoscale_budget_amount{currency_code="USD",project_name="proj-dev"}`)
+	require.NotContains(t, output, `lcp_api_autoscale_budget_utilization_ratio{project_name="proj-dev"}`)
+	require.NotContains(t, output, `lcp_api_autoscale_budget_amount{currency_code="USD",project_name="proj-uat"}`)
+	require.NotContains(t, output, `lcp_api_autoscale_budget_utilization_ratio{project_name="proj-uat"}`)
+	require.Contains(t, output, `lcp_api_autoscale_budget_amount{currency_code="USD",project_name="acme"} 100`)
+	require.Contains(t, output, `lcp_api_autoscale_budget_utilization_ratio{project_name="acme"} 0.9`)
+	require.NotContains(t, output, `lcp_api_autoscale_budget_utilization_ratio{project_name="acme-prd"}`)
+	require.NotContains(t, output, `lcp_api_autoscale_budget_utilization_ratio{project_name="acme-dev"}`)
+}
+
+func TestAutoscaleCollector_History(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-1", OrganizationId: "proj-1"}}
+
+	mockJSON := `{
+		"activationHistory": [
+			{"projectId": "proj-1", "serviceId": "liferay", "enabledAt": 1740900000000, "disabledAt": 1740910000000, "enabledByEmail": "user@liferay.com"},
+			{"projectId": "proj-1", "serviceId": "liferay", "enabledAt": 1740920000000, "disabledAt": 0, "enabledByEmail": "user@liferay.com"},
+			{"projectId": "proj-1", "serviceId": "webserver", "enabledAt": 1740900000000, "disabledAt": 1740910000000, "disabledByEmail": "user@liferay.com"}
+		],
+		"includedChildProjectIds": ["proj-1"],
+		"scaleHistory": [
+			{"projectId": "proj-1", "serviceId": "liferay", "numAdditionalInstances": 1, "startedAt": 1740927000000, "endedAt": 1740927120000, "activeTimePerInstanceMs": 120000},
+			{"projectId": "proj-1", "serviceId": "liferay", "numAdditionalInstances": 2, "startedAt": 1740928000000, "endedAt": 1740928300000, "activeTimePerInstanceMs": 300000},
+			{"projectId": "proj-1", "serviceId": "liferay", "numAdditionalInstances": 3, "startedAt": 1740929000000, "endedAt": 0, "activeTimePerInstanceMs": 0}
+		],
+		"subtotalsByProjectId": {"proj-1": {"cost": {"amount": 1, "currency": "USD"}}}
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprintln(w, mockJSON)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	options := AutoscaleOptions{
+		Window: internal.DateWindow{Mode: internal.DateWindowMonth, Clock: func() time.Time { return now }},
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	_, err := reg.Gather()
+	require.NoError(t, err)
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	require.Contains(t, output, `lcp_api_autoscale_scaling_events_total{project_name="proj-1",service_id="liferay"} 2`)
+	require.Contains(t, output, `lcp_api_autoscale_scaling_instance_seconds_total{project_name="proj-1",service_id="liferay"} 720`)
+	require.Contains(t, output, `lcp_api_autoscale_scaling_event_duration_seconds_sum{project_name="proj-1",service_id="liferay"} 420`)
+	require.Contains(t, output, `lcp_api_autoscale_scaling_event_duration_seconds_count{project_name="proj-1",service_id="liferay"} 2`)
+	require.Contains(t, output, `lcp_api_autoscale_scaling_event_additional_instances_sum{project_name="proj-1",service_id="liferay"} 3`)
+	require.Contains(t, output, `lcp_api_autoscale_enabled{project_name="proj-1",service_id="liferay"} 1`)
+	require.Contains(t, output, `lcp_api_autoscale_enabled{project_name="proj-1",service_id="webserver"} 0`)
+	require.NotContains(t, output, `lcp_api_autoscale_scaling_history_duration_ms`)
+	require.NotContains(t, output, `lcp_api_autoscale_activation_history_count`)
+	require.NotContains(t, output, `user@liferay.com`)
+}
+
+func TestAutoscaleCollector_PublishesEventsOnce(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-1", OrganizationId: "proj-1"}}
+
+	mockJSON := `{
+		"activationHistory": [
+			{"projectId": "proj-1", "serviceId": "liferay", "enabledAt": 1740900000000, "disabledAt": 1740910000000, "enabledByEmail": "user@liferay.com"}
+		],
+		"includedChildProjectIds": ["proj-1"],
+		"scaleHistory": [
+			{"projectId": "proj-1", "serviceId": "liferay", "numAdditionalInstances": 1, "startedAt": 1740927000000, "endedAt": 1740927120000},
+			{"projectId": "proj-1", "serviceId": "liferay", "numAdditionalInstances": 3, "startedAt": 1740929000000, "endedAt": 0}
+		],
+		"subtotalsByProjectId": {"proj-1": {"cost": {"amount": 1, "currency": "USD"}}}
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprintln(w, mockJSON)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	options := AutoscaleOptions{
+		Window: internal.DateWindow{Mode: internal.DateWindowMonth, Clock: func() time.Time { return now }},
+		Events: events.NewDispatcher(nil, events.NewLogSink(&buf)),
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	_, err := reg.Gather()
+	require.NoError(t, err)
+	_, err = reg.Gather()
+	require.NoError(t, err)
+
+	var kinds []string
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var event events.Event
+		require.NoError(t, decoder.Decode(&event))
+		kinds = append(kinds, event.Kind)
+	}
+
+	require.Equal(t, []string{"autoscale_enabled", "autoscale_disabled", "autoscale_scaling"}, kinds)
+}
+
+func TestAutoscaleCollector_ScaleLimits(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-1", OrganizationId: "proj-1"}}
+
+	mockJSON := `{
+		"activationHistory": [
+			{
+				"projectId": "proj-1",
+				"serviceId": "liferay",
+				"availability": "HA",
+				"enabledAt": 1740900000000,
+				"disabledAt": 0,
+				"minInstances": 1,
+				"maxInstances": 3,
+				"scaleLimitsChanges": [
+					{"changedAt": 1740910000000, "changedByEmail": "user@liferay.com", "minInstances": 1, "maxInstances": 5},
+					{"changedAt": 1740920000000, "changedByEmail": "user@liferay.com", "minInstances": 2, "maxInstances": 6},
+					{"changedAt": 1740000000000, "changedByEmail": "user@liferay.com", "minInstances": 1, "maxInstances": 4}
+				]
+			},
+			{
+				"projectId": "proj-1",
+				"serviceId": "webserver",
+				"enabledAt": 1740900000000,
+				"disabledAt": 1740910000000,
+				"minInstances": 1,
+				"maxInstances": 2,
+				"scaleLimitsChanges": []
+			}
+		],
+		"includedChildProjectIds": ["proj-1"],
+		"scaleHistory": [],
+		"subtotalsByProjectId": {"proj-1": {"cost": {"amount": 1, "currency": "USD"}}}
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprintln(w, mockJSON)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	options := AutoscaleOptions{
+		Window: internal.DateWindow{Mode: internal.DateWindowMonth, Clock: func() time.Time { return now }},
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	_, err := reg.Gather()
+	require.NoError(t, err)
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	require.Contains(t, output, `lcp_api_autoscale_min_instances{project_name="proj-1",service_id="liferay"} 2`)
+	require.Contains(t, output, `lcp_api_autoscale_max_instances{project_name="proj-1",service_id="liferay"} 6`)
+	require.Contains(t, output, `lcp_api_autoscale_scale_limit_changes_total{project_name="proj-1",service_id="liferay"} 2`)
+	require.NotContains(t, output, `lcp_api_autoscale_max_instances{project_name="proj-1",service_id="webserver"}`)
+}
+
+func TestAutoscaleCollector_Batching(t *testing.T) {
+	var projects []shared.Projects
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		projects = append(projects, shared.Projects{ProjectID: id, OrganizationId: id})
+	}
+	projectProvider := staticProjects(projects)
+
+	var mu sync.Mutex
+	var batches []string
+	var inFlight, maxInFlight atomic.Int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			observed := maxInFlight.Load()
+			if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		projectIds := strings.Split(r.URL.Query().Get("projectIds"), ",")
+		mu.Lock()
+		batches = append(batches, r.URL.Query().Get("projectIds"))
+		mu.Unlock()
+
+		var children, subtotals []string
+		for _, id := range projectIds {
+			children = append(children, fmt.Sprintf(`"%s-prd"`, id))
+			subtotals = append(subtotals, fmt.Sprintf(`"%s-prd": {"cost": {"amount": 1, "currency": "USD"}}`, id))
+		}
+		_, err := fmt.Fprintf(w, `{"includedChildProjectIds": [%s], "subtotalsByProjectId": {%s}}`, strings.Join(children, ","), strings.Join(subtotals, ","))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	options := AutoscaleOptions{
+		Window:      internal.DateWindow{Mode: internal.DateWindowDay},
+		BatchSize:   2,
+		Concurrency: 2,
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"a,b", "c,d", "e"}, batches)
+	require.LessOrEqual(t, maxInFlight.Load(), int64(2))
+
+	for _, family := range families {
+		if family.GetName() == "lcp_api_autoscale_cost_amount" {
+			require.Len(t, family.GetMetric(), 5)
+			return
+		}
+	}
+	t.Fatal("lcp_api_autoscale_cost_amount not found")
+}
+
+func TestAutoscaleCollector_BatchFailureFailsCollection(t *testing.T) {
+	projectProvider := staticProjects{
+		{ProjectID: "a", OrganizationId: "a"},
+		{ProjectID: "b", OrganizationId: "b"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("projectIds") == "b" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, err := fmt.Fprintln(w, `{"includedChildProjectIds": ["a-prd"], "subtotalsByProjectId": {"a-prd": {}}}`)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	options := AutoscaleOptions{
+		Window:      internal.DateWindow{Mode: internal.DateWindowDay},
+		BatchSize:   1,
+		Concurrency: 2,
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	ch := make(chan prometheus.Metric, 100)
+	err := collector.Update(context.Background(), ch)
+	require.ErrorContains(t, err, "batch 2/2")
+	require.Empty(t, ch)
+}
+
+func TestAutoscaleCollector_Reconciliation(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj", OrganizationId: "proj"}}
+
+	mockJSON := `{
+		"includedChildProjectIds": ["proj-prd", "proj-uat"],
+		"subtotalsByProjectId": {
+			"proj-prd": {"billableTimeMs": 3600000, "cost": {"amount": 12, "currency": "USD"}},
+			"proj-dev": {"billableTimeMs": 1800000, "cost": {"amount": 6, "currency": "USD"}}
+		}
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprintln(w, mockJSON)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	options := AutoscaleOptions{
+		Window: internal.DateWindow{Mode: internal.DateWindowDay},
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	require.Contains(t, output, `lcp_api_autoscale_cost_amount{currency_code="USD",project_name="proj-prd"} 12`)
+	require.Contains(t, output, `lcp_api_autoscale_cost_amount{currency_code="USD",project_name="proj-dev"} 6`)
+	require.NotContains(t, output, `lcp_api_autoscale_cost_amount{currency_code="",project_name="proj-uat"}`)
+	require.Contains(t, output, `lcp_api_autoscale_missing_subtotal{project_name="proj-prd"} 0`)
+	require.Contains(t, output, `lcp_api_autoscale_missing_subtotal{project_name="proj-uat"} 1`)
+	require.Contains(t, output, `lcp_api_autoscale_orphan_subtotal{project_name="proj-prd"} 0`)
+	require.Contains(t, output, `lcp_api_autoscale_orphan_subtotal{project_name="proj-dev"} 1`)
+}
+
+func TestAutoscaleCollector_ExchangeRates(t *testing.T) {
+	projectProvider := staticProjects{
+		{ProjectID: "proj", OrganizationId: "proj"},
+		{ProjectID: "proj-prd", OrganizationId: "proj"},
+		{ProjectID: "proj-uat", OrganizationId: "proj"},
+		{ProjectID: "proj-dev", OrganizationId: "proj"},
+	}
+
+	mockJSON := `{
+		"includedChildProjectIds": ["proj-prd", "proj-uat", "proj-dev"],
+		"subtotalsByProjectId": {
+			"proj-prd": {"cost": {"amount": 40, "currency": "EUR"}},
+			"proj-uat": {"cost": {"amount": 15, "currency": "USD"}},
+			"proj-dev": {"cost": {"amount": 5, "currency": "JPY"}}
+		}
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprintln(w, mockJSON)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "rates.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("target: usd\nrates:\n  eur: 1.5\n"), 0o600))
+
+	rates, err := config.NewExchangeRatesFile(path)
+	require.NoError(t, err)
+
+	options := AutoscaleOptions{
+		Window:        internal.DateWindow{Mode: internal.DateWindowMonth},
+		ExchangeRates: rates,
+	}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewAutoscaleCollector(client, projectProvider, options)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	require.Contains(t, output, `lcp_api_autoscale_cost_normalized_amount{currency_code="USD",project_name="proj-prd"} 60`)
+	require.Contains(t, output, `lcp_api_autoscale_cost_normalized_amount{currency_code="USD",project_name="proj-uat"} 15`)
+	require.NotContains(t, output, `lcp_api_autoscale_cost_normalized_amount{currency_code="USD",project_name="proj-dev"}`)
+	require.Contains(t, output, `lcp_api_autoscale_cost_amount{currency_code="EUR",project_name="proj-prd"} 40`)
+}
+
+func TestAutoscaleCollector_ProjectFilter(t *testing.T) {
+	var requestedProjectIDs string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload string
+		switch r.URL.Path {
+		case "/admin/projects":
+			payload = `[
+				{"id": "acme", "projectId": "acme", "organizationId": "acme"},
+				{"id": "acme-prd", "projectId": "acme-prd", "organizationId": "acme", "metadata": {"subscription": {"envType": "PRODUCTION"}}},
+				{"id": "acme-dev", "projectId": "acme-dev", "organizationId": "acme", "metadata": {"subscription": {"envType": "DEVELOPMENT"}}}
+			]`
+		case "/admin/reports/autoscale/stats":
+			requestedProjectIDs = r.URL.Query().Get("projectIds")
+			payload = `{
+				"activationHistory": [{"projectId": "acme-dev", "serviceId": "liferay", "enabledAt": 1740900000000}],
+				"includedChildProjectIds": ["acme-prd", "acme-dev"],
+				"subtotalsByProjectId": {
+					"acme-prd": {"cost": {"amount": 12, "currency": "USD"}},
+					"acme-dev": {"cost": {"amount": 6, "currency": "USD"}}
+				}
+			}`
+		}
+		_, err := fmt.Fprintln(w, payload)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "filter.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("exclude:\n  - envTypes: [development]\n  - projectId: \"^acme$\"\n"), 0o600))
+	filter, err := config.LoadProjectFilter(path)
+	require.NoError(t, err)
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	inventory := NewProjectInventory(client, time.Minute, filter)
+	collector := NewAutoscaleCollector(client, inventory, AutoscaleOptions{
+		Window: internal.DateWindow{Mode: internal.DateWindowDay},
+	})
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	require.Equal(t, "acme", requestedProjectIDs)
+	require.Contains(t, output, `lcp_api_autoscale_cost_amount{currency_code="USD",project_name="acme-prd"} 12`)
+	require.NotContains(t, output, `project_name="acme-dev"`)
+	require.NotContains(t, output, `service_id="liferay"`)
+}
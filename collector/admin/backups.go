@@ -0,0 +1,175 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+var backupStates = []string{"failed", "in_progress", "succeeded"}
+
+type backupsCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	concurrency     int
+
+	automatedEnabled     *prometheus.Desc
+	lastStatus           *prometheus.Desc
+	lastSuccessSizeBytes *prometheus.Desc
+	lastSuccessTimestamp *prometheus.Desc
+	retained             *prometheus.Desc
+}
+
+func NewBackupsCollector(client *lcp.Client, provider ProjectProvider, concurrency int) *backupsCollector {
+	fqName := internal.Name("backups")
+
+	return &backupsCollector{
+		client:          client,
+		projectProvider: provider,
+		concurrency:     concurrency,
+		automatedEnabled: prometheus.NewDesc(
+			fqName("automated_enabled"),
+			"Whether automated backups are enabled. 1 if enabled, 0 otherwise",
+			[]string{"project_name"},
+			nil,
+		),
+		lastStatus: prometheus.NewDesc(
+			fqName("last_status"),
+			"Outcome of the latest backup by project. 1 for the current state, 0 otherwise",
+			[]string{"project_name", "status"},
+			nil,
+		),
+		lastSuccessSizeBytes: prometheus.NewDesc(
+			fqName("last_success_size_bytes"),
+			"Size of the latest successful backup in bytes by project",
+			[]string{"project_name"},
+			nil,
+		),
+		lastSuccessTimestamp: prometheus.NewDesc(
+			fqName("last_success_timestamp_seconds"),
+			"Timestamp of the latest successful backup by project",
+			[]string{"project_name"},
+			nil,
+		),
+		retained: prometheus.NewDesc(
+			fqName("retained"),
+			"Number of backups retained by project, whatever their status",
+			[]string{"project_name"},
+			nil,
+		),
+	}
+}
+
+func (bc *backupsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- bc.automatedEnabled
+	ch <- bc.lastStatus
+	ch <- bc.lastSuccessSizeBytes
+	ch <- bc.lastSuccessTimestamp
+	ch <- bc.retained
+}
+
+func (bc *backupsCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = bc.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (bc *backupsCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := bc.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("BackupsCollector", "No projects found")
+		return nil
+	}
+
+	return forEachProject(ctx, "BackupsCollector", projects, bc.concurrency, ch, func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error {
+		projectPath := fmt.Sprintf("/projects/%s", url.PathEscape(projectID))
+
+		backups, err := lcp.FetchFrom[shared.Backup](ctx, bc.client, projectPath+"/backups", nil)
+		if err != nil {
+			return fmt.Errorf("backups: %w", err)
+		}
+
+		settings, err := lcp.FetchOneFrom[shared.BackupSettings](ctx, bc.client, projectPath+"/backups/settings", nil)
+		if err != nil {
+			return fmt.Errorf("backup settings: %w", err)
+		}
+
+		bc.collectBackups(ch, projectID, backups, settings)
+		return nil
+	})
+}
+
+func (bc *backupsCollector) collectBackups(ch chan<- prometheus.Metric, projectID string, backups []shared.Backup, settings *shared.BackupSettings) {
+	ch <- prometheus.MustNewConstMetric(
+		bc.automatedEnabled,
+		prometheus.GaugeValue,
+		internal.BoolToFloat(settings.Automated),
+		projectID,
+	)
+
+	var latest, lastSuccess *shared.Backup
+	for i, backup := range backups {
+		if strings.ToLower(backup.Status) == "succeeded" {
+			if lastSuccess == nil || backupCompletedAt(backup) > backupCompletedAt(*lastSuccess) {
+				lastSuccess = &backups[i]
+			}
+		}
+		if latest == nil || backup.CreatedAt > latest.CreatedAt {
+			latest = &backups[i]
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		bc.retained,
+		prometheus.GaugeValue,
+		float64(len(backups)),
+		projectID,
+	)
+
+	if lastSuccess != nil {
+		ch <- prometheus.MustNewConstMetric(
+			bc.lastSuccessTimestamp,
+			prometheus.GaugeValue,
+			internal.MillisToSeconds(backupCompletedAt(*lastSuccess)),
+			projectID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			bc.lastSuccessSizeBytes,
+			prometheus.GaugeValue,
+			float64(lastSuccess.Size),
+			projectID,
+		)
+	}
+
+	if latest == nil {
+		return
+	}
+
+	if !collectStateSet(ch, bc.lastStatus, backupStates, strings.ToLower(latest.Status), projectID) {
+		internal.LogWarn("BackupsCollector", "Unknown backup status %q for project %s", latest.Status, projectID)
+	}
+}
+
+func backupCompletedAt(backup shared.Backup) int64 {
+	if backup.FinishedAt > 0 {
+		return backup.FinishedAt
+	}
+	return backup.CreatedAt
+}
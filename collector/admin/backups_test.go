@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestBackupsCollector(t *testing.T) {
+	projectProvider := staticProjects{
+		{ProjectID: "proj-prd", OrganizationId: "proj"},
+		{ProjectID: "proj-dev", OrganizationId: "proj"},
+	}
+
+	responses := map[string]string{
+		"/projects/proj-prd/backups": `[
+			{"id": "b1", "projectId": "proj-prd", "status": "SUCCEEDED", "createdAt": 1740830400000, "size": 1073741824},
+			{"id": "b2", "projectId": "proj-prd", "status": "SUCCEEDED", "createdAt": 1740916800000, "finishedAt": 1740920400000, "size": 2147483648},
+			{"id": "b3", "projectId": "proj-prd", "status": "FAILED", "createdAt": 1741003200000}
+		]`,
+		"/projects/proj-prd/backups/settings": `{"automated": true, "retentionDays": 30, "schedule": "0 2 * * *"}`,
+		"/projects/proj-dev/backups":          `[]`,
+		"/projects/proj-dev/backups/settings": `{"automated": false, "retentionDays": 0, "schedule": ""}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := responses[r.URL.Path]
+		require.True(t, ok, "unexpected path %s", r.URL.Path)
+		_, err := fmt.Fprintln(w, payload)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewBackupsCollector(client, projectProvider, 2)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	assert.Contains(t, output, `lcp_api_backups_automated_enabled{project_name="proj-prd"} 1`)
+	assert.Contains(t, output, `lcp_api_backups_automated_enabled{project_name="proj-dev"} 0`)
+	assert.Contains(t, output, `lcp_api_backups_retained{project_name="proj-prd"} 3`)
+	assert.Contains(t, output, `lcp_api_backups_retained{project_name="proj-dev"} 0`)
+	assert.Contains(t, output, `lcp_api_backups_last_success_timestamp_seconds{project_name="proj-prd"} 1.7409204e+09`)
+	assert.Contains(t, output, `lcp_api_backups_last_success_size_bytes{project_name="proj-prd"} 2.147483648e+09`)
+	assert.Contains(t, output, `lcp_api_backups_last_status{project_name="proj-prd",status="failed"} 1`)
+	assert.Contains(t, output, `lcp_api_backups_last_status{project_name="proj-prd",status="succeeded"} 0`)
+	assert.NotContains(t, output, `lcp_api_backups_last_success_timestamp_seconds{project_name="proj-dev"}`)
+	assert.NotContains(t, output, `lcp_api_backups_last_status{project_name="proj-dev"`)
+}
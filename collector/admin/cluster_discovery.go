@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -63,16 +64,16 @@ func (c *clusterDiscoveryCollector) Collect(ch chan<- prometheus.Metric) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		c.collectMetrics(ch)
+		_ = c.Update(context.Background(), ch)
 	}()
 	wg.Wait()
 }
 
-func (c *clusterDiscoveryCollector) collectMetrics(ch chan<- prometheus.Metric) {
-	clusters, err := lcp.FetchFrom[shared.ClusterDiscovery](c.client, "/admin/cluster-discovery/discovered-clusters", nil)
+func (c *clusterDiscoveryCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	clusters, err := lcp.FetchFrom[shared.ClusterDiscovery](ctx, c.client, "/admin/cluster-discovery/discovered-clusters", nil)
 	if err != nil {
 		internal.LogError("ClusterDiscoveryCollector", "Failed to fetch discovered clusters: %v", err)
-		return
+		return err
 	}
 
 	ch <- prometheus.MustNewConstMetric(
@@ -111,4 +112,6 @@ func (c *clusterDiscoveryCollector) collectMetrics(ch chan<- prometheus.Metric)
 			lcpClusterName,
 		)
 	}
+
+	return nil
 }
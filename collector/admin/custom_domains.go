@@ -0,0 +1,150 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+var domainVerificationStates = []string{"failed", "pending", "verified"}
+
+type customDomainsCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	concurrency     int
+
+	certNotAfter  *prometheus.Desc
+	certNotBefore *prometheus.Desc
+	info          *prometheus.Desc
+	verification  *prometheus.Desc
+}
+
+func NewCustomDomainsCollector(client *lcp.Client, provider ProjectProvider, concurrency int) *customDomainsCollector {
+	fqName := internal.Name("custom_domains")
+	labels := []string{"project_name", "service_id", "domain"}
+
+	return &customDomainsCollector{
+		client:          client,
+		projectProvider: provider,
+		concurrency:     concurrency,
+		certNotAfter: prometheus.NewDesc(
+			fqName("cert_not_after_timestamp_seconds"),
+			"Timestamp after which the certificate of the custom domain is no longer valid",
+			labels,
+			nil,
+		),
+		certNotBefore: prometheus.NewDesc(
+			fqName("cert_not_before_timestamp_seconds"),
+			"Timestamp before which the certificate of the custom domain is not yet valid",
+			labels,
+			nil,
+		),
+		info: prometheus.NewDesc(
+			fqName("info"),
+			"Custom domains with the issuer type of their certificate (letsencrypt or custom)",
+			[]string{"project_name", "service_id", "domain", "issuer_type"},
+			nil,
+		),
+		verification: prometheus.NewDesc(
+			fqName("verification_status"),
+			"Verification status of the custom domain. 1 for the current state, 0 otherwise",
+			[]string{"project_name", "service_id", "domain", "status"},
+			nil,
+		),
+	}
+}
+
+func (dc *customDomainsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dc.certNotAfter
+	ch <- dc.certNotBefore
+	ch <- dc.info
+	ch <- dc.verification
+}
+
+func (dc *customDomainsCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = dc.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (dc *customDomainsCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := dc.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("CustomDomainsCollector", "No projects found")
+		return nil
+	}
+
+	return forEachProject(ctx, "CustomDomainsCollector", projects, dc.concurrency, ch, func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error {
+		path := fmt.Sprintf("/projects/%s/custom-domains", url.PathEscape(projectID))
+		domains, err := lcp.FetchFrom[shared.CustomDomain](ctx, dc.client, path, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, domain := range domains {
+			dc.collectDomain(ch, projectID, domain)
+		}
+		return nil
+	})
+}
+
+func (dc *customDomainsCollector) collectDomain(ch chan<- prometheus.Metric, projectID string, domain shared.CustomDomain) {
+	status := strings.ToLower(domain.VerificationStatus)
+	if !collectStateSet(ch, dc.verification, domainVerificationStates, status, projectID, domain.ServiceID, domain.Domain) {
+		internal.LogWarn("CustomDomainsCollector", "Unknown verification status %q for domain %s", domain.VerificationStatus, domain.Domain)
+	}
+
+	if domain.Certificate == "" {
+		return
+	}
+
+	cert, err := internal.ParseBase64Certificate(domain.Certificate)
+	if err != nil {
+		internal.LogWarn("CustomDomainsCollector", "Invalid certificate for domain %s: %v", domain.Domain, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		dc.info,
+		prometheus.GaugeValue,
+		1.0,
+		projectID,
+		domain.ServiceID,
+		domain.Domain,
+		internal.CertificateIssuerType(cert),
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		dc.certNotBefore,
+		prometheus.GaugeValue,
+		float64(cert.NotBefore.Unix()),
+		projectID,
+		domain.ServiceID,
+		domain.Domain,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		dc.certNotAfter,
+		prometheus.GaugeValue,
+		float64(cert.NotAfter.Unix()),
+		projectID,
+		domain.ServiceID,
+		domain.Domain,
+	)
+}
@@ -0,0 +1,95 @@
+package admin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func testCertificate(t *testing.T, organization string, notBefore, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{Organization: []string{organization}},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCustomDomainsCollector(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-prd", OrganizationId: "proj"}}
+
+	letsEncrypt := testCertificate(t, "Let's Encrypt", time.Unix(1740000000, 0), time.Unix(1747776000, 0))
+	custom := testCertificate(t, "Acme Corp", time.Unix(1730000000, 0), time.Unix(1761536000, 0))
+
+	mockJSON := fmt.Sprintf(`[
+		{"domain": "www.acme.com", "serviceId": "webserver", "verificationStatus": "VERIFIED", "certificate": %q},
+		{"domain": "shop.acme.com", "serviceId": "webserver", "verificationStatus": "VERIFIED", "certificate": %q},
+		{"domain": "new.acme.com", "serviceId": "webserver", "verificationStatus": "PENDING"},
+		{"domain": "bad.acme.com", "serviceId": "webserver", "verificationStatus": "VERIFIED", "certificate": "not-a-certificate"}
+	]`, letsEncrypt, custom)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/projects/proj-prd/custom-domains", r.URL.Path)
+		_, err := fmt.Fprintln(w, mockJSON)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewCustomDomainsCollector(client, projectProvider, 1)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	assert.Contains(t, output, `lcp_api_custom_domains_info{domain="www.acme.com",issuer_type="letsencrypt",project_name="proj-prd",service_id="webserver"} 1`)
+	assert.Contains(t, output, `lcp_api_custom_domains_info{domain="shop.acme.com",issuer_type="custom",project_name="proj-prd",service_id="webserver"} 1`)
+	assert.Contains(t, output, `lcp_api_custom_domains_cert_not_before_timestamp_seconds{domain="www.acme.com",project_name="proj-prd",service_id="webserver"} 1.74e+09`)
+	assert.Contains(t, output, `lcp_api_custom_domains_cert_not_after_timestamp_seconds{domain="www.acme.com",project_name="proj-prd",service_id="webserver"} 1.747776e+09`)
+	assert.Contains(t, output, `lcp_api_custom_domains_cert_not_after_timestamp_seconds{domain="shop.acme.com",project_name="proj-prd",service_id="webserver"} 1.761536e+09`)
+	assert.Contains(t, output, `lcp_api_custom_domains_verification_status{domain="new.acme.com",project_name="proj-prd",service_id="webserver",status="pending"} 1`)
+	assert.Contains(t, output, `lcp_api_custom_domains_verification_status{domain="new.acme.com",project_name="proj-prd",service_id="webserver",status="verified"} 0`)
+	assert.Contains(t, output, `lcp_api_custom_domains_verification_status{domain="bad.acme.com",project_name="proj-prd",service_id="webserver",status="verified"} 1`)
+	assert.NotContains(t, output, `lcp_api_custom_domains_info{domain="new.acme.com"`)
+	assert.NotContains(t, output, `lcp_api_custom_domains_cert_not_after_timestamp_seconds{domain="bad.acme.com"`)
+}
@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+var deploymentStates = []string{"canceled", "failed", "pending", "running", "succeeded"}
+
+type deploymentsCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	window          internal.DateWindow
+	concurrency     int
+
+	mu         sync.Mutex
+	seenBuilds map[string]int64
+
+	buildDuration *prometheus.HistogramVec
+
+	failed               *prometheus.Desc
+	lastStatus           *prometheus.Desc
+	lastSuccessTimestamp *prometheus.Desc
+}
+
+func NewDeploymentsCollector(client *lcp.Client, provider ProjectProvider, window internal.DateWindow, concurrency int) *deploymentsCollector {
+	fqName := internal.Name("deployments")
+
+	return &deploymentsCollector{
+		client:          client,
+		projectProvider: provider,
+		window:          window,
+		concurrency:     concurrency,
+		seenBuilds:      make(map[string]int64),
+		buildDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    internal.Name("builds")("duration_seconds"),
+				Help:    "Duration of finished builds in seconds by project",
+				Buckets: prometheus.ExponentialBuckets(30, 2, 8),
+			},
+			[]string{"project_name"},
+		),
+		failed: prometheus.NewDesc(
+			fqName("failed"),
+			"Number of failed deployments in the current window by project",
+			[]string{"project_name"},
+			nil,
+		),
+		lastStatus: prometheus.NewDesc(
+			fqName("last_status"),
+			"Status of the latest deployment by project. 1 for the current state, 0 otherwise",
+			[]string{"project_name", "status"},
+			nil,
+		),
+		lastSuccessTimestamp: prometheus.NewDesc(
+			fqName("last_success_timestamp_seconds"),
+			"Timestamp of the latest successful deployment by project",
+			[]string{"project_name"},
+			nil,
+		),
+	}
+}
+
+func (dc *deploymentsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dc.failed
+	ch <- dc.lastStatus
+	ch <- dc.lastSuccessTimestamp
+	dc.buildDuration.Describe(ch)
+}
+
+func (dc *deploymentsCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = dc.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (dc *deploymentsCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := dc.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("DeploymentsCollector", "No projects found")
+		return nil
+	}
+
+	dataRange := dc.window.Range()
+	from := internal.DateRangeStartMillis(dataRange)
+	queryParams := map[string]string{
+		"start": dataRange.From,
+		"end":   dataRange.End,
+	}
+
+	err = forEachProject(ctx, "DeploymentsCollector", projects, dc.concurrency, ch, func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error {
+		projectPath := fmt.Sprintf("/projects/%s", url.PathEscape(projectID))
+
+		deployments, err := lcp.FetchFrom[shared.Deployment](ctx, dc.client, projectPath+"/deployments", queryParams)
+		if err != nil {
+			return fmt.Errorf("deployments: %w", err)
+		}
+
+		// The latest deployments are looked up without the window, which may
+		// not reach back to them.
+		latest, err := lcp.FetchFrom[shared.Deployment](ctx, dc.client, projectPath+"/deployments", map[string]string{"limit": "1"})
+		if err != nil {
+			return fmt.Errorf("latest deployment: %w", err)
+		}
+
+		succeeded, err := lcp.FetchFrom[shared.Deployment](ctx, dc.client, projectPath+"/deployments", map[string]string{"status": "succeeded", "limit": "1"})
+		if err != nil {
+			return fmt.Errorf("latest successful deployment: %w", err)
+		}
+
+		builds, err := lcp.FetchFrom[shared.Build](ctx, dc.client, projectPath+"/builds", queryParams)
+		if err != nil {
+			return fmt.Errorf("builds: %w", err)
+		}
+
+		dc.collectDeployments(ch, projectID, deployments, latest, succeeded)
+		dc.observeBuilds(projectID, builds, from)
+		return nil
+	})
+
+	dc.mu.Lock()
+	for key, at := range dc.seenBuilds {
+		if at < from {
+			delete(dc.seenBuilds, key)
+		}
+	}
+	dc.mu.Unlock()
+
+	dc.buildDuration.Collect(ch)
+
+	return err
+}
+
+func (dc *deploymentsCollector) collectDeployments(ch chan<- prometheus.Metric, projectID string, windowed, latest, succeeded []shared.Deployment) {
+	var failed int
+	for _, deployment := range windowed {
+		if strings.ToLower(deployment.Status) == "failed" {
+			failed++
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		dc.failed,
+		prometheus.GaugeValue,
+		float64(failed),
+		projectID,
+	)
+
+	var lastSuccess int64
+	for _, deployment := range succeeded {
+		if strings.ToLower(deployment.Status) == "succeeded" {
+			lastSuccess = max(lastSuccess, deployment.FinishedAt)
+		}
+	}
+
+	if lastSuccess > 0 {
+		ch <- prometheus.MustNewConstMetric(
+			dc.lastSuccessTimestamp,
+			prometheus.GaugeValue,
+			internal.MillisToSeconds(lastSuccess),
+			projectID,
+		)
+	}
+
+	var newest *shared.Deployment
+	for i, deployment := range latest {
+		if newest == nil || deployment.CreatedAt > newest.CreatedAt {
+			newest = &latest[i]
+		}
+	}
+
+	if newest == nil {
+		return
+	}
+
+	if !collectStateSet(ch, dc.lastStatus, deploymentStates, strings.ToLower(newest.Status), projectID) {
+		internal.LogWarn("DeploymentsCollector", "Unknown deployment status %q for project %s", newest.Status, projectID)
+	}
+}
+
+func (dc *deploymentsCollector) observeBuilds(projectID string, builds []shared.Build, from int64) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	for _, build := range builds {
+		if build.FinishedAt == 0 || build.StartedAt == 0 || build.FinishedAt < from {
+			continue
+		}
+
+		key := projectID + "/" + build.ID
+		if _, seen := dc.seenBuilds[key]; seen {
+			continue
+		}
+		dc.seenBuilds[key] = build.FinishedAt
+
+		dc.buildDuration.WithLabelValues(projectID).Observe(internal.MillisToSeconds(build.FinishedAt - build.StartedAt))
+	}
+}
@@ -0,0 +1,156 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestDeploymentsCollector(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-prd", OrganizationId: "proj"}}
+
+	type poll struct {
+		windowed, latest, succeeded string
+	}
+	polls := []poll{
+		{
+			windowed: `[
+				{"id": "d1", "projectId": "proj-prd", "status": "SUCCEEDED", "createdAt": 1740916800000, "finishedAt": 1740917100000},
+				{"id": "d2", "projectId": "proj-prd", "status": "FAILED", "createdAt": 1740920400000, "finishedAt": 1740920700000},
+				{"id": "d3", "projectId": "proj-prd", "status": "RUNNING", "createdAt": 1740924000000}
+			]`,
+			latest:    `[{"id": "d3", "projectId": "proj-prd", "status": "RUNNING", "createdAt": 1740924000000}]`,
+			succeeded: `[{"id": "d1", "projectId": "proj-prd", "status": "SUCCEEDED", "createdAt": 1740916800000, "finishedAt": 1740917100000}]`,
+		},
+		{
+			// Nothing was deployed within the window.
+			windowed:  `[]`,
+			latest:    `[{"id": "d3", "projectId": "proj-prd", "status": "FAILED", "createdAt": 1740924000000, "finishedAt": 1740924300000}]`,
+			succeeded: `[{"id": "d1", "projectId": "proj-prd", "status": "SUCCEEDED", "createdAt": 1740916800000, "finishedAt": 1740917100000}]`,
+		},
+	}
+	buildsJSON := `[
+		{"id": "b1", "projectId": "proj-prd", "status": "SUCCEEDED", "startedAt": 1740916000000, "finishedAt": 1740916120000},
+		{"id": "b2", "projectId": "proj-prd", "status": "FAILED", "startedAt": 1740920000000, "finishedAt": 1740920600000},
+		{"id": "b3", "projectId": "proj-prd", "status": "RUNNING", "startedAt": 1740923000000}
+	]`
+
+	var current atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		p := polls[current.Load()]
+
+		var payload string
+		switch {
+		case r.URL.Path == "/projects/proj-prd/builds":
+			require.Equal(t, "2025-03-02T00:00:00Z", query.Get("start"))
+			payload = buildsJSON
+		case r.URL.Path != "/projects/proj-prd/deployments":
+			t.Errorf("unexpected path %s", r.URL.Path)
+		case query.Has("start"):
+			require.Equal(t, "2025-03-02T00:00:00Z", query.Get("start"))
+			payload = p.windowed
+		case query.Get("status") == "succeeded":
+			require.Equal(t, "1", query.Get("limit"))
+			payload = p.succeeded
+		default:
+			require.Equal(t, "1", query.Get("limit"))
+			payload = p.latest
+		}
+		_, err := fmt.Fprintln(w, payload)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 2, 18, 0, 0, 0, time.UTC)
+	window := internal.DateWindow{Mode: internal.DateWindowDay, Clock: func() time.Time { return now }}
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewDeploymentsCollector(client, projectProvider, window, 1)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	scrape := func() string {
+		resp, err := http.Get(serverMetrics.URL)
+		require.NoError(t, err)
+		defer func() {
+			closeErr := resp.Body.Close()
+			require.NoError(t, closeErr)
+		}()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	output := scrape()
+
+	require.Contains(t, output, `lcp_api_deployments_failed{project_name="proj-prd"} 1`)
+	require.Contains(t, output, `lcp_api_deployments_last_success_timestamp_seconds{project_name="proj-prd"} 1.7409171e+09`)
+	require.Contains(t, output, `lcp_api_deployments_last_status{project_name="proj-prd",status="running"} 1`)
+	require.Contains(t, output, `lcp_api_deployments_last_status{project_name="proj-prd",status="failed"} 0`)
+	require.Contains(t, output, `lcp_api_builds_duration_seconds_count{project_name="proj-prd"} 2`)
+	require.Contains(t, output, `lcp_api_builds_duration_seconds_sum{project_name="proj-prd"} 720`)
+	require.Contains(t, output, `lcp_api_builds_duration_seconds_bucket{project_name="proj-prd",le="120"} 1`)
+
+	current.Store(1)
+	output = scrape()
+
+	require.Contains(t, output, `lcp_api_deployments_failed{project_name="proj-prd"} 0`)
+	require.Contains(t, output, `lcp_api_deployments_last_success_timestamp_seconds{project_name="proj-prd"} 1.7409171e+09`)
+	require.Contains(t, output, `lcp_api_deployments_last_status{project_name="proj-prd",status="failed"} 1`)
+	require.Contains(t, output, `lcp_api_deployments_last_status{project_name="proj-prd",status="running"} 0`)
+	require.Contains(t, output, `lcp_api_builds_duration_seconds_count{project_name="proj-prd"} 2`)
+}
+
+func TestDeploymentsCollector_UnknownStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := `[]`
+		if r.URL.Path == "/projects/proj-prd/deployments" {
+			payload = `[{"id": "d1", "projectId": "proj-prd", "status": "ROLLING_BACK", "createdAt": 1740916800000}]`
+		}
+		_, err := fmt.Fprintln(w, payload)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	window := internal.DateWindow{Mode: internal.DateWindowDay}
+	collector := NewDeploymentsCollector(lcp.NewClient(server.URL, "fake-token"), staticProjects{{ProjectID: "proj-prd"}}, window, 1)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	require.Contains(t, output, `lcp_api_deployments_last_status{project_name="proj-prd",status="rolling_back"} 1`)
+	require.Contains(t, output, `lcp_api_deployments_last_status{project_name="proj-prd",status="succeeded"} 0`)
+	require.NotContains(t, output, `lcp_api_deployments_last_success_timestamp_seconds`)
+}
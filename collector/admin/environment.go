@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+// environmentCollector only ever reads names and timestamps of environment
+// variables and secrets; values are never decoded, logged or exported.
+type environmentCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	concurrency     int
+
+	secrets               *prometheus.Desc
+	secretsHash           *prometheus.Desc
+	secretsLastModified   *prometheus.Desc
+	variables             *prometheus.Desc
+	variablesHash         *prometheus.Desc
+	variablesLastModified *prometheus.Desc
+}
+
+func NewEnvironmentCollector(client *lcp.Client, provider ProjectProvider, concurrency int) *environmentCollector {
+	fqName := internal.Name("environment")
+	serviceLabels := []string{"project_name", "service_id"}
+
+	return &environmentCollector{
+		client:          client,
+		projectProvider: provider,
+		concurrency:     concurrency,
+		secrets: prometheus.NewDesc(
+			fqName("secrets"),
+			"Number of secrets per project",
+			[]string{"project_name"},
+			nil,
+		),
+		secretsHash: prometheus.NewDesc(
+			fqName("secrets_hash_info"),
+			"Stable hash of the secret names per project",
+			[]string{"project_name", "hash"},
+			nil,
+		),
+		secretsLastModified: prometheus.NewDesc(
+			fqName("secrets_last_modified_timestamp_seconds"),
+			"Timestamp of the latest secret change per project",
+			[]string{"project_name"},
+			nil,
+		),
+		variables: prometheus.NewDesc(
+			fqName("variables"),
+			"Number of environment variables per service",
+			serviceLabels,
+			nil,
+		),
+		variablesHash: prometheus.NewDesc(
+			fqName("variables_hash_info"),
+			"Stable hash of the environment variable names per service",
+			[]string{"project_name", "service_id", "hash"},
+			nil,
+		),
+		variablesLastModified: prometheus.NewDesc(
+			fqName("variables_last_modified_timestamp_seconds"),
+			"Timestamp of the latest environment variable change per service",
+			serviceLabels,
+			nil,
+		),
+	}
+}
+
+func (ec *environmentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ec.secrets
+	ch <- ec.secretsHash
+	ch <- ec.secretsLastModified
+	ch <- ec.variables
+	ch <- ec.variablesHash
+	ch <- ec.variablesLastModified
+}
+
+func (ec *environmentCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = ec.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (ec *environmentCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := ec.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("EnvironmentCollector", "No projects found")
+		return nil
+	}
+
+	return forEachProject(ctx, "EnvironmentCollector", projects, ec.concurrency, ch, func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error {
+		projectPath := fmt.Sprintf("/projects/%s", url.PathEscape(projectID))
+
+		secrets, err := lcp.FetchFrom[shared.ConfigEntry](ctx, ec.client, projectPath+"/secrets", nil)
+		if err != nil {
+			return fmt.Errorf("secrets: %w", err)
+		}
+
+		services, err := fetchServices(ctx, ec.client, projectID)
+		if err != nil {
+			return fmt.Errorf("services: %w", err)
+		}
+
+		ec.collectEntries(ch, ec.secrets, ec.secretsHash, ec.secretsLastModified, secrets, projectID)
+
+		for _, service := range services {
+			path := fmt.Sprintf("%s/services/%s/environment-variables", projectPath, url.PathEscape(service.ServiceID))
+			variables, err := lcp.FetchFrom[shared.ConfigEntry](ctx, ec.client, path, nil)
+			if err != nil {
+				return fmt.Errorf("environment variables of service %s: %w", service.ServiceID, err)
+			}
+
+			ec.collectEntries(ch, ec.variables, ec.variablesHash, ec.variablesLastModified, variables, projectID, service.ServiceID)
+		}
+		return nil
+	})
+}
+
+func (ec *environmentCollector) collectEntries(ch chan<- prometheus.Metric, count, hash, lastModified *prometheus.Desc, entries []shared.ConfigEntry, labelValues ...string) {
+	names := make([]string, 0, len(entries))
+	var updatedAt int64
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+		updatedAt = max(updatedAt, entry.UpdatedAt)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		count,
+		prometheus.GaugeValue,
+		float64(len(entries)),
+		labelValues...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		hash,
+		prometheus.GaugeValue,
+		1.0,
+		append(slices.Clone(labelValues), internal.KeySetHash(names))...,
+	)
+
+	if updatedAt > 0 {
+		ch <- prometheus.MustNewConstMetric(
+			lastModified,
+			prometheus.GaugeValue,
+			internal.MillisToSeconds(updatedAt),
+			labelValues...,
+		)
+	}
+}
@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestEnvironmentCollector(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-prd", OrganizationId: "proj"}}
+
+	responses := map[string]string{
+		"/projects/proj-prd/secrets": `[
+			{"name": "DB_PASSWORD", "value": "s3cr3t-db-password", "updatedAt": 1740916800000},
+			{"name": "API_TOKEN", "value": "s3cr3t-api-token", "updatedAt": 1740920400000}
+		]`,
+		"/projects/proj-prd/services":                                 `[{"serviceId": "liferay"}, {"serviceId": "webserver"}]`,
+		"/projects/proj-prd/services/liferay/environment-variables":   `[{"name": "LIFERAY_JVM_OPTS", "value": "-Xmx8g", "updatedAt": 1740830400000}, {"name": "LCP_SECRET_KEY", "value": "s3cr3t-env-value", "updatedAt": 1740844800000}]`,
+		"/projects/proj-prd/services/webserver/environment-variables": `[]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := responses[r.URL.Path]
+		require.True(t, ok, "unexpected path %s", r.URL.Path)
+		_, err := fmt.Fprintln(w, payload)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	out, level := logrus.StandardLogger().Out, logrus.GetLevel()
+	logrus.SetOutput(&logs)
+	logrus.SetLevel(logrus.DebugLevel)
+	defer func() {
+		logrus.SetOutput(out)
+		logrus.SetLevel(level)
+	}()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewEnvironmentCollector(client, projectProvider, 1)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	assert.Contains(t, output, `lcp_api_environment_secrets{project_name="proj-prd"} 2`)
+	assert.Contains(t, output, fmt.Sprintf(`lcp_api_environment_secrets_hash_info{hash="%s",project_name="proj-prd"} 1`, internal.KeySetHash([]string{"API_TOKEN", "DB_PASSWORD"})))
+	assert.Contains(t, output, `lcp_api_environment_secrets_last_modified_timestamp_seconds{project_name="proj-prd"} 1.7409204e+09`)
+	assert.Contains(t, output, `lcp_api_environment_variables{project_name="proj-prd",service_id="liferay"} 2`)
+	assert.Contains(t, output, `lcp_api_environment_variables{project_name="proj-prd",service_id="webserver"} 0`)
+	assert.Contains(t, output, fmt.Sprintf(`lcp_api_environment_variables_hash_info{hash="%s",project_name="proj-prd",service_id="liferay"} 1`, internal.KeySetHash([]string{"LCP_SECRET_KEY", "LIFERAY_JVM_OPTS"})))
+	assert.Contains(t, output, `lcp_api_environment_variables_last_modified_timestamp_seconds{project_name="proj-prd",service_id="liferay"} 1.7408448e+09`)
+	assert.NotContains(t, output, `lcp_api_environment_variables_last_modified_timestamp_seconds{project_name="proj-prd",service_id="webserver"}`)
+
+	for _, value := range []string{"s3cr3t-db-password", "s3cr3t-api-token", "s3cr3t-env-value", "-Xmx8g"} {
+		assert.NotContains(t, output, value)
+		assert.NotContains(t, logs.String(), value)
+	}
+}
+
+func TestEnvironmentCollector_DoesNotLogValuesOnFailure(t *testing.T) {
+	projectProvider := staticProjects{
+		{ProjectID: "proj-prd", OrganizationId: "proj"},
+		{ProjectID: "proj-dev", OrganizationId: "proj"},
+		{ProjectID: "proj-uat", OrganizationId: "proj"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/proj-prd/secrets":
+			_, err := fmt.Fprintln(w, `[{"name": "DB_PASSWORD", "value": "s3cr3t-db-password", "updatedAt": "2025-01-01"}]`)
+			require.NoError(t, err)
+		case "/projects/proj-dev/secrets":
+			w.WriteHeader(http.StatusBadRequest)
+			_, err := fmt.Fprintln(w, `{"message": "invalid secret s3cr3t-echoed-value"}`)
+			require.NoError(t, err)
+		case "/projects/proj-uat/secrets":
+			_, err := fmt.Fprintln(w, `{"status": 400, "message": "invalid secret s3cr3t-envelope-value", "data": {}}`)
+			require.NoError(t, err)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	out, level := logrus.StandardLogger().Out, logrus.GetLevel()
+	logrus.SetOutput(&logs)
+	logrus.SetLevel(logrus.DebugLevel)
+	defer func() {
+		logrus.SetOutput(out)
+		logrus.SetLevel(level)
+	}()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewEnvironmentCollector(client, projectProvider, 1)
+
+	ch := make(chan prometheus.Metric, 100)
+	err := collector.Update(t.Context(), ch)
+	close(ch)
+	require.Error(t, err)
+
+	assert.Contains(t, logs.String(), "Failed to unmarshal response body")
+	assert.Contains(t, logs.String(), "API error status: 400")
+	for _, value := range []string{"s3cr3t-db-password", "s3cr3t-echoed-value", "s3cr3t-envelope-value"} {
+		assert.NotContains(t, logs.String(), value)
+		assert.NotContains(t, err.Error(), value)
+	}
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/scheduler"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+)
+
+// forEachProject runs fn for every project, see fanOut.
+func forEachProject(ctx context.Context, component string, projects []shared.Projects, concurrency int, ch chan<- prometheus.Metric, fn func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error) error {
+	projectIDs := make([]string, 0, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ProjectID)
+	}
+	return fanOut(ctx, component, "project", projectIDs, concurrency, ch, fn)
+}
+
+// fanOut runs fn for every ID with bounded concurrency. The metrics of each
+// call are buffered and only forwarded to ch when it succeeds, so a failure
+// halfway never leaves part of the series behind. When every call failed the
+// errors are returned as is and the scheduler keeps the previous snapshot;
+// when only some failed a *scheduler.PartialError reports how many.
+func fanOut(ctx context.Context, component, kind string, ids []string, concurrency int, ch chan<- prometheus.Metric, fn func(ctx context.Context, id string, ch chan<- prometheus.Metric) error) error {
+	sem := make(chan struct{}, max(concurrency, 1))
+	errs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			metrics, err := bufferMetrics(func(buf chan<- prometheus.Metric) error {
+				return fn(ctx, id, buf)
+			})
+			if err != nil {
+				internal.LogError(component, "Failed to collect %s %s: %v", kind, id, err)
+				errs[i] = fmt.Errorf("%s %s: %w", kind, id, err)
+				return
+			}
+
+			for _, metric := range metrics {
+				ch <- metric
+			}
+		}(i, id)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+
+	switch {
+	case failed == 0:
+		return nil
+	case failed == len(ids):
+		return errors.Join(errs...)
+	default:
+		return &scheduler.PartialError{Failed: failed, Total: len(ids), Err: errors.Join(errs...)}
+	}
+}
+
+func bufferMetrics(fn func(ch chan<- prometheus.Metric) error) ([]prometheus.Metric, error) {
+	ch := make(chan prometheus.Metric)
+	done := make(chan struct{})
+
+	var metrics []prometheus.Metric
+	go func() {
+		defer close(done)
+		for metric := range ch {
+			metrics = append(metrics, metric)
+		}
+	}()
+
+	err := fn(ch)
+	close(ch)
+	<-done
+	return metrics, err
+}
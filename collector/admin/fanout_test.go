@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal/scheduler"
+)
+
+func TestFanOut_DropsMetricsOfFailedCalls(t *testing.T) {
+	desc := prometheus.NewDesc("lcp_api_fake_value", "Fake value", []string{"project_name"}, nil)
+
+	ch := make(chan prometheus.Metric, 10)
+	err := fanOut(context.Background(), "Test", "project", []string{"proj-1", "proj-2", "proj-3"}, 2, ch, func(_ context.Context, id string, ch chan<- prometheus.Metric) error {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1, id)
+		if id == "proj-2" {
+			return fmt.Errorf("failed halfway")
+		}
+		return nil
+	})
+	close(ch)
+
+	var partial *scheduler.PartialError
+	require.True(t, errors.As(err, &partial))
+	assert.Equal(t, 1, partial.Failed)
+	assert.Equal(t, 3, partial.Total)
+	assert.ErrorContains(t, err, "project proj-2: failed halfway")
+
+	var metrics []string
+	for metric := range ch {
+		metrics = append(metrics, metric.Desc().String())
+	}
+	assert.Len(t, metrics, 2)
+}
+
+func TestFanOut_AllCallsFail(t *testing.T) {
+	ch := make(chan prometheus.Metric)
+	err := fanOut(context.Background(), "Test", "project", []string{"proj-1", "proj-2"}, 1, ch, func(context.Context, string, chan<- prometheus.Metric) error {
+		return fmt.Errorf("unavailable")
+	})
+
+	require.Error(t, err)
+	var partial *scheduler.PartialError
+	assert.False(t, errors.As(err, &partial))
+}
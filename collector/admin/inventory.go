@@ -0,0 +1,186 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/config"
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+type ProjectProvider interface {
+	Projects(ctx context.Context) ([]shared.Projects, error)
+	Excluded(projectID string) bool
+}
+
+// ProjectInventory caches the project list for a TTL so that every collector
+// works against the same snapshot instead of refetching on each scrape.
+type ProjectInventory struct {
+	client *lcp.Client
+	ttl    time.Duration
+	filter *config.ProjectFilter
+	now    func() time.Time
+
+	// refreshMu serializes fetches so that slow refreshes never hold mu,
+	// which scrapes and Excluded take to read the cached fields.
+	refreshMu sync.Mutex
+
+	mu        sync.RWMutex
+	projects  []shared.Projects
+	excluded  map[string]struct{}
+	fetchedAt time.Time
+
+	refreshErrors prometheus.Counter
+	age           *prometheus.Desc
+	filtered      *prometheus.Desc
+	total         *prometheus.Desc
+}
+
+func NewProjectInventory(client *lcp.Client, ttl time.Duration, filter *config.ProjectFilter) *ProjectInventory {
+	fqName := internal.ExporterName("inventory")
+
+	return &ProjectInventory{
+		client: client,
+		ttl:    ttl,
+		filter: filter,
+		now:    time.Now,
+		refreshErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fqName("refresh_errors_total"),
+			Help: "Total number of failed project inventory refreshes",
+		}),
+		age: prometheus.NewDesc(
+			fqName("age_seconds"),
+			"Seconds since the project inventory was last refreshed",
+			nil, nil,
+		),
+		filtered: prometheus.NewDesc(
+			fqName("filtered_projects"),
+			"Number of projects excluded from the inventory by the project filter",
+			nil, nil,
+		),
+		total: prometheus.NewDesc(
+			fqName("projects"),
+			"Number of projects in the inventory after filtering",
+			nil, nil,
+		),
+	}
+}
+
+func (pi *ProjectInventory) Describe(ch chan<- *prometheus.Desc) {
+	pi.refreshErrors.Describe(ch)
+	ch <- pi.age
+	ch <- pi.filtered
+	ch <- pi.total
+}
+
+func (pi *ProjectInventory) Collect(ch chan<- prometheus.Metric) {
+	pi.refreshErrors.Collect(ch)
+
+	pi.mu.RLock()
+	defer pi.mu.RUnlock()
+
+	if pi.fetchedAt.IsZero() {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(pi.age, prometheus.GaugeValue, pi.now().Sub(pi.fetchedAt).Seconds())
+	ch <- prometheus.MustNewConstMetric(pi.filtered, prometheus.GaugeValue, float64(len(pi.excluded)))
+	ch <- prometheus.MustNewConstMetric(pi.total, prometheus.GaugeValue, float64(len(pi.projects)))
+}
+
+// WarmUp loads the inventory before any collector runs.
+func (pi *ProjectInventory) WarmUp(ctx context.Context) error {
+	pi.refreshMu.Lock()
+	defer pi.refreshMu.Unlock()
+
+	if err := pi.refresh(ctx); err != nil {
+		return err
+	}
+
+	if projects, _ := pi.cached(); len(projects) == 0 {
+		internal.LogWarn("ProjectInventory", "Warm-up returned 0 projects")
+	}
+	return nil
+}
+
+// Projects returns the cached project list, refreshing it once the TTL has
+// expired. When a refresh fails the previous list is served until it succeeds.
+func (pi *ProjectInventory) Projects(ctx context.Context) ([]shared.Projects, error) {
+	if projects, fresh := pi.cached(); fresh {
+		return projects, nil
+	}
+
+	pi.refreshMu.Lock()
+	defer pi.refreshMu.Unlock()
+
+	// Another caller may have refreshed while this one was waiting.
+	if projects, fresh := pi.cached(); fresh {
+		return projects, nil
+	}
+
+	if err := pi.refresh(ctx); err != nil {
+		pi.mu.RLock()
+		defer pi.mu.RUnlock()
+
+		if pi.fetchedAt.IsZero() {
+			return nil, err
+		}
+		internal.LogWarn("ProjectInventory", "Serving stale inventory: %v", err)
+		return pi.projects, nil
+	}
+
+	projects, _ := pi.cached()
+	return projects, nil
+}
+
+// Excluded reports whether the project was dropped by the project filter, so
+// collectors can skip data the API returns for it under an included parent.
+func (pi *ProjectInventory) Excluded(projectID string) bool {
+	pi.mu.RLock()
+	defer pi.mu.RUnlock()
+
+	_, ok := pi.excluded[projectID]
+	return ok
+}
+
+func (pi *ProjectInventory) cached() ([]shared.Projects, bool) {
+	pi.mu.RLock()
+	defer pi.mu.RUnlock()
+
+	return pi.projects, !pi.fetchedAt.IsZero() && pi.now().Sub(pi.fetchedAt) < pi.ttl
+}
+
+// refresh must be called with refreshMu held.
+func (pi *ProjectInventory) refresh(ctx context.Context) error {
+	projects, err := lcp.FetchFrom[shared.Projects](ctx, pi.client, "/admin/projects", nil)
+	if err != nil {
+		pi.refreshErrors.Inc()
+		internal.LogError("ProjectInventory", "Failed to fetch projects: %v", err)
+		return fmt.Errorf("fetch projects: %w", err)
+	}
+
+	var kept []shared.Projects
+	excluded := make(map[string]struct{})
+	for _, project := range projects {
+		if pi.filter.Match(project) {
+			kept = append(kept, project)
+		} else {
+			excluded[project.ProjectID] = struct{}{}
+		}
+	}
+	internal.LogDebug("ProjectInventory", "Kept %d of %d projects", len(kept), len(projects))
+
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+
+	pi.projects = kept
+	pi.excluded = excluded
+	pi.fetchedAt = pi.now()
+	return nil
+}
@@ -0,0 +1,163 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/config"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+type staticProjects []shared.Projects
+
+func (s staticProjects) Projects(context.Context) ([]shared.Projects, error) {
+	return s, nil
+}
+
+func (s staticProjects) Excluded(string) bool {
+	return false
+}
+
+func TestProjectInventory_CachesUntilTTL(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/admin/projects", r.URL.Path)
+		n := requests.Add(1)
+		_, err := fmt.Fprintf(w, `[{"id": "proj-%d", "projectId": "proj-%d"}]`, n, n)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	inventory := NewProjectInventory(lcp.NewClient(server.URL, "fake-token"), time.Minute, nil)
+	inventory.now = func() time.Time { return now }
+
+	require.NoError(t, inventory.WarmUp(context.Background()))
+	require.EqualValues(t, 1, requests.Load())
+
+	projects, err := inventory.Projects(context.Background())
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, "proj-1", projects[0].ProjectID)
+	require.EqualValues(t, 1, requests.Load())
+
+	now = now.Add(time.Minute)
+	projects, err = inventory.Projects(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "proj-2", projects[0].ProjectID)
+	require.EqualValues(t, 2, requests.Load())
+}
+
+func TestProjectInventory_ServesStaleOnError(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "boom", http.StatusBadRequest)
+			return
+		}
+		_, err := fmt.Fprintln(w, `[{"id": "proj-1", "projectId": "proj-1"}]`)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	inventory := NewProjectInventory(lcp.NewClient(server.URL, "fake-token"), 0, nil)
+
+	_, err := inventory.Projects(context.Background())
+	require.NoError(t, err)
+
+	fail.Store(true)
+	projects, err := inventory.Projects(context.Background())
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, 1.0, testutil.ToFloat64(inventory.refreshErrors))
+}
+
+func TestProjectInventory_FailsWithoutSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	inventory := NewProjectInventory(lcp.NewClient(server.URL, "fake-token"), time.Minute, nil)
+
+	require.Error(t, inventory.WarmUp(context.Background()))
+	_, err := inventory.Projects(context.Background())
+	require.Error(t, err)
+}
+
+func TestProjectInventory_RefreshDoesNotBlockReaders(t *testing.T) {
+	var requests atomic.Int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			<-release
+		}
+		_, err := fmt.Fprintln(w, `[{"id": "proj-1", "projectId": "proj-1"}]`)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	inventory := NewProjectInventory(lcp.NewClient(server.URL, "fake-token"), 0, nil)
+	require.NoError(t, inventory.WarmUp(context.Background()))
+
+	go func() {
+		_, _ = inventory.Projects(context.Background())
+	}()
+	require.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+	var count int
+	var excluded bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		count = testutil.CollectAndCount(inventory)
+		excluded = inventory.Excluded("proj-1")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("readers blocked by an in-flight refresh")
+	}
+	require.Equal(t, 4, count)
+	require.False(t, excluded)
+}
+
+func TestProjectInventory_AppliesFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := fmt.Fprintln(w, `[
+			{"id": "acme", "projectId": "acme", "organizationId": "acme"},
+			{"id": "acme-prd", "projectId": "acme-prd", "organizationId": "acme", "metadata": {"subscription": {"envType": "PRODUCTION"}}},
+			{"id": "acme-dev", "projectId": "acme-dev", "organizationId": "acme", "metadata": {"subscription": {"envType": "DEVELOPMENT"}}}
+		]`)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "filter.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("include:\n  envTypes: [production]\n"), 0o600))
+	filter, err := config.LoadProjectFilter(path)
+	require.NoError(t, err)
+
+	inventory := NewProjectInventory(lcp.NewClient(server.URL, "fake-token"), time.Minute, filter)
+
+	projects, err := inventory.Projects(context.Background())
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	require.Equal(t, "acme-prd", projects[0].ProjectID)
+	require.True(t, inventory.Excluded("acme-dev"))
+	require.True(t, inventory.Excluded("acme"))
+	require.False(t, inventory.Excluded("acme-prd"))
+}
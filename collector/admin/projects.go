@@ -1,23 +1,16 @@
 package admin
 
 import (
-	"sync"
+	"context"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/jullianow/lcp-exporter/internal"
 	"github.com/jullianow/lcp-exporter/internal/shared"
-	"github.com/jullianow/lcp-exporter/lcp"
 )
 
-type ProjectProvider interface {
-	GetProjects() []shared.Projects
-}
-
 type ProjectsCollector struct {
-	client   *lcp.Client
-	projects []shared.Projects
-	mu       sync.RWMutex
+	inventory ProjectProvider
 
 	collaborators      *prometheus.Desc
 	create             *prometheus.Desc
@@ -29,11 +22,11 @@ type ProjectsCollector struct {
 	volumeStorageBytes *prometheus.Desc
 }
 
-func NewProjectsCollector(client *lcp.Client) *ProjectsCollector {
+func NewProjectsCollector(inventory ProjectProvider) *ProjectsCollector {
 	fqName := internal.Name("projects")
 
 	return &ProjectsCollector{
-		client: client,
+		inventory: inventory,
 		collaborators: prometheus.NewDesc(
 			fqName("collaborators"),
 			"Number of collaborators per project",
@@ -114,38 +107,15 @@ func (c *ProjectsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.volumeStorageBytes
 }
 
-func (pc *ProjectsCollector) GetProjects() []shared.Projects {
-	pc.mu.RLock()
-	defer pc.mu.RUnlock()
-	return pc.projects
-}
-
-func (pc *ProjectsCollector) FetchInitial() {
-	projects := pc.fetch()
-	if len(projects) == 0 {
-		internal.LogWarn("ProjectsCollector", "Initial fetch returned 0 projects")
-	}
-
-	pc.mu.Lock()
-	pc.projects = projects
-	pc.mu.Unlock()
+func (pc *ProjectsCollector) Collect(ch chan<- prometheus.Metric) {
+	_ = pc.Update(context.Background(), ch)
 }
 
-func (pc *ProjectsCollector) fetch() []shared.Projects {
-	projects, err := lcp.FetchFrom[shared.Projects](pc.client, "/admin/projects", nil)
+func (pc *ProjectsCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := pc.inventory.Projects(ctx)
 	if err != nil {
-		internal.LogError("ProjectsCollector", "Failed to fetch projects: %v", err)
-		return nil
+		return err
 	}
-	return projects
-}
-
-func (pc *ProjectsCollector) Collect(ch chan<- prometheus.Metric) {
-	projects := pc.fetch()
-
-	pc.mu.Lock()
-	pc.projects = projects
-	pc.mu.Unlock()
 
 	ch <- prometheus.MustNewConstMetric(
 		pc.total,
@@ -233,4 +203,6 @@ func (pc *ProjectsCollector) Collect(ch chan<- prometheus.Metric) {
 			}
 		}
 	}
+
+	return nil
 }
@@ -80,7 +80,7 @@ func TestProjectsCollector(t *testing.T) {
 	defer server.Close()
 
 	client := lcp.NewClient(server.URL, "fake-token")
-	collector := NewProjectsCollector(client)
+	collector := NewProjectsCollector(NewProjectInventory(client, 0, nil))
 
 	reg := prometheus.NewRegistry()
 	require.NoError(t, reg.Register(collector))
@@ -0,0 +1,237 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+type servicesCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	concurrency     int
+
+	cpuLimitCores      *prometheus.Desc
+	cpuRequestCores    *prometheus.Desc
+	health             *prometheus.Desc
+	info               *prometheus.Desc
+	instances          *prometheus.Desc
+	memoryLimitBytes   *prometheus.Desc
+	memoryRequestBytes *prometheus.Desc
+	scale              *prometheus.Desc
+	status             *prometheus.Desc
+	total              *prometheus.Desc
+}
+
+func NewServicesCollector(client *lcp.Client, provider ProjectProvider, concurrency int) *servicesCollector {
+	fqName := internal.Name("services")
+	labels := []string{"project_name", "service_id"}
+
+	return &servicesCollector{
+		client:          client,
+		projectProvider: provider,
+		concurrency:     concurrency,
+		cpuLimitCores: prometheus.NewDesc(
+			fqName("cpu_limit_cores"),
+			"CPU limit of the service in cores",
+			labels,
+			nil,
+		),
+		cpuRequestCores: prometheus.NewDesc(
+			fqName("cpu_request_cores"),
+			"CPU request of the service in cores",
+			labels,
+			nil,
+		),
+		health: prometheus.NewDesc(
+			fqName("health"),
+			"Health of the service. 1 if is healthy, 0 otherwise",
+			labels,
+			nil,
+		),
+		info: prometheus.NewDesc(
+			fqName("info"),
+			"Image and version of the service",
+			[]string{"project_name", "service_id", "image", "version"},
+			nil,
+		),
+		instances: prometheus.NewDesc(
+			fqName("instances"),
+			"Number of running instances of the service",
+			labels,
+			nil,
+		),
+		memoryLimitBytes: prometheus.NewDesc(
+			fqName("memory_limit_bytes"),
+			"Memory limit of the service in bytes",
+			labels,
+			nil,
+		),
+		memoryRequestBytes: prometheus.NewDesc(
+			fqName("memory_request_bytes"),
+			"Memory request of the service in bytes",
+			labels,
+			nil,
+		),
+		scale: prometheus.NewDesc(
+			fqName("scale"),
+			"Number of instances the service is configured to run",
+			labels,
+			nil,
+		),
+		status: prometheus.NewDesc(
+			fqName("status"),
+			"Status of the service. 1 if is running, 0 otherwise",
+			labels,
+			nil,
+		),
+		total: prometheus.NewDesc(
+			fqName("total"),
+			"Total number of services per project",
+			[]string{"project_name"},
+			nil,
+		),
+	}
+}
+
+func (sc *servicesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sc.cpuLimitCores
+	ch <- sc.cpuRequestCores
+	ch <- sc.health
+	ch <- sc.info
+	ch <- sc.instances
+	ch <- sc.memoryLimitBytes
+	ch <- sc.memoryRequestBytes
+	ch <- sc.scale
+	ch <- sc.status
+	ch <- sc.total
+}
+
+func (sc *servicesCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = sc.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (sc *servicesCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := sc.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("ServicesCollector", "No projects found")
+		return nil
+	}
+
+	return forEachProject(ctx, "ServicesCollector", projects, sc.concurrency, ch, func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error {
+		services, err := fetchServices(ctx, sc.client, projectID)
+		if err != nil {
+			return err
+		}
+
+		sc.collectServices(ch, projectID, services)
+		return nil
+	})
+}
+
+func fetchServices(ctx context.Context, client *lcp.Client, projectID string) ([]shared.Service, error) {
+	path := fmt.Sprintf("/projects/%s/services", url.PathEscape(projectID))
+	return lcp.FetchFrom[shared.Service](ctx, client, path, nil)
+}
+
+func (sc *servicesCollector) collectServices(ch chan<- prometheus.Metric, projectID string, services []shared.Service) {
+	ch <- prometheus.MustNewConstMetric(
+		sc.total,
+		prometheus.GaugeValue,
+		float64(len(services)),
+		projectID,
+	)
+
+	for _, service := range services {
+		ch <- prometheus.MustNewConstMetric(
+			sc.status,
+			prometheus.GaugeValue,
+			internal.BoolToFloat(service.Status == "running"),
+			projectID,
+			service.ServiceID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.health,
+			prometheus.GaugeValue,
+			internal.BoolToFloat(service.Health == "healthy"),
+			projectID,
+			service.ServiceID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.scale,
+			prometheus.GaugeValue,
+			float64(service.Scale),
+			projectID,
+			service.ServiceID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.instances,
+			prometheus.GaugeValue,
+			float64(service.Instances),
+			projectID,
+			service.ServiceID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.cpuRequestCores,
+			prometheus.GaugeValue,
+			service.Requests.CPU,
+			projectID,
+			service.ServiceID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.cpuLimitCores,
+			prometheus.GaugeValue,
+			service.Limits.CPU,
+			projectID,
+			service.ServiceID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.memoryRequestBytes,
+			prometheus.GaugeValue,
+			float64(internal.MiBToBytes(service.Requests.Memory)),
+			projectID,
+			service.ServiceID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.memoryLimitBytes,
+			prometheus.GaugeValue,
+			float64(internal.MiBToBytes(service.Limits.Memory)),
+			projectID,
+			service.ServiceID,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.info,
+			prometheus.GaugeValue,
+			1.0,
+			projectID,
+			service.ServiceID,
+			service.Image,
+			internal.ImageVersion(service.Image),
+		)
+	}
+}
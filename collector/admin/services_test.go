@@ -0,0 +1,119 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestServicesCollector(t *testing.T) {
+	projectProvider := staticProjects{
+		{ProjectID: "proj-prd", OrganizationId: "proj"},
+		{ProjectID: "proj-broken", OrganizationId: "proj"},
+	}
+
+	mockJSON := `[
+	{
+		"serviceId": "liferay",
+		"projectId": "proj-prd",
+		"status": "running",
+		"health": "healthy",
+		"image": "liferaycloud/liferay-dxp:7.4.13-u112",
+		"scale": 2,
+		"instances": 2,
+		"requests": {"cpu": 2, "memory": 4096},
+		"limits": {"cpu": 4, "memory": 8192}
+	},
+	{
+		"serviceId": "search",
+		"projectId": "proj-prd",
+		"status": "stopped",
+		"health": "unhealthy",
+		"image": "liferaycloud/elasticsearch:7.17.24-5.2.0",
+		"scale": 1,
+		"instances": 0,
+		"requests": {"cpu": 0.5, "memory": 2048},
+		"limits": {"cpu": 1, "memory": 4096}
+	}
+	]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/projects/proj-broken/services" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		require.Equal(t, "/projects/proj-prd/services", r.URL.Path)
+		_, err := fmt.Fprintln(w, mockJSON)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewServicesCollector(client, projectProvider, 2)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	assert.Contains(t, output, `lcp_api_services_total{project_name="proj-prd"} 2`)
+	assert.Contains(t, output, `lcp_api_services_status{project_name="proj-prd",service_id="liferay"} 1`)
+	assert.Contains(t, output, `lcp_api_services_status{project_name="proj-prd",service_id="search"} 0`)
+	assert.Contains(t, output, `lcp_api_services_health{project_name="proj-prd",service_id="liferay"} 1`)
+	assert.Contains(t, output, `lcp_api_services_health{project_name="proj-prd",service_id="search"} 0`)
+	assert.Contains(t, output, `lcp_api_services_scale{project_name="proj-prd",service_id="liferay"} 2`)
+	assert.Contains(t, output, `lcp_api_services_instances{project_name="proj-prd",service_id="search"} 0`)
+	assert.Contains(t, output, `lcp_api_services_cpu_request_cores{project_name="proj-prd",service_id="search"} 0.5`)
+	assert.Contains(t, output, `lcp_api_services_cpu_limit_cores{project_name="proj-prd",service_id="liferay"} 4`)
+	assert.Contains(t, output, `lcp_api_services_memory_request_bytes{project_name="proj-prd",service_id="liferay"} 4.294967296e+09`)
+	assert.Contains(t, output, `lcp_api_services_memory_limit_bytes{project_name="proj-prd",service_id="liferay"} 8.589934592e+09`)
+	assert.Contains(t, output, `lcp_api_services_info{image="liferaycloud/liferay-dxp:7.4.13-u112",project_name="proj-prd",service_id="liferay",version="7.4.13-u112"} 1`)
+	assert.NotContains(t, output, `project_name="proj-broken"`)
+}
+
+func TestServicesCollector_AllProjectsFail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewServicesCollector(client, staticProjects{{ProjectID: "proj-prd"}}, 1)
+
+	ch := make(chan prometheus.Metric, 10)
+	err := collector.Update(context.Background(), ch)
+	require.Error(t, err)
+	require.Empty(t, ch)
+}
+
+func TestServicesCollector_NoProjects(t *testing.T) {
+	collector := NewServicesCollector(lcp.NewClient("http://unused", "fake-token"), staticProjects([]shared.Projects{}), 1)
+
+	ch := make(chan prometheus.Metric, 10)
+	require.NoError(t, collector.Update(context.Background(), ch))
+	require.Empty(t, ch)
+}
@@ -0,0 +1,29 @@
+package admin
+
+import (
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+)
+
+// collectStateSet emits one series per state with 1 for the current state.
+// Unknown states are appended so they are never silently dropped; it reports
+// whether the current state was one of the known ones.
+func collectStateSet(ch chan<- prometheus.Metric, desc *prometheus.Desc, states []string, current string, labelValues ...string) bool {
+	known := slices.Contains(states, current)
+	if !known {
+		states = append(slices.Clone(states), current)
+	}
+
+	for _, state := range states {
+		ch <- prometheus.MustNewConstMetric(
+			desc,
+			prometheus.GaugeValue,
+			internal.BoolToFloat(state == current),
+			append(slices.Clone(labelValues), state)...,
+		)
+	}
+	return known
+}
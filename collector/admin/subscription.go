@@ -0,0 +1,152 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+type subscriptionCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	concurrency     int
+
+	info             *prometheus.Desc
+	quotaLimit       *prometheus.Desc
+	quotaUsed        *prometheus.Desc
+	quotaUtilization *prometheus.Desc
+}
+
+func NewSubscriptionCollector(client *lcp.Client, provider ProjectProvider, concurrency int) *subscriptionCollector {
+	fqName := internal.Name("subscription")
+	labels := []string{"organization_id", "resource"}
+
+	return &subscriptionCollector{
+		client:          client,
+		projectProvider: provider,
+		concurrency:     concurrency,
+		info: prometheus.NewDesc(
+			fqName("info"),
+			"Subscription plan of the organization",
+			[]string{"organization_id", "plan"},
+			nil,
+		),
+		quotaLimit: prometheus.NewDesc(
+			fqName("quota_limit"),
+			"Quota allowed by the subscription per organization and resource",
+			labels,
+			nil,
+		),
+		quotaUsed: prometheus.NewDesc(
+			fqName("quota_used"),
+			"Quota currently consumed per organization and resource",
+			labels,
+			nil,
+		),
+		quotaUtilization: prometheus.NewDesc(
+			fqName("quota_utilization_ratio"),
+			"Ratio of the consumed quota to the subscription limit per organization and resource",
+			labels,
+			nil,
+		),
+	}
+}
+
+func (sc *subscriptionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sc.info
+	ch <- sc.quotaLimit
+	ch <- sc.quotaUsed
+	ch <- sc.quotaUtilization
+}
+
+func (sc *subscriptionCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = sc.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (sc *subscriptionCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := sc.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("SubscriptionCollector", "No projects found")
+		return nil
+	}
+
+	// Subscriptions belong to the organization, which is the root project.
+	organizationIDs := internal.GetRootProjectIDs(projects)
+
+	return fanOut(ctx, "SubscriptionCollector", "organization", organizationIDs, sc.concurrency, ch, func(ctx context.Context, organizationID string, ch chan<- prometheus.Metric) error {
+		path := fmt.Sprintf("/organizations/%s/subscription", url.PathEscape(organizationID))
+		subscription, err := lcp.FetchOneFrom[shared.Subscription](ctx, sc.client, path, nil)
+		if err != nil {
+			return err
+		}
+
+		sc.collectSubscription(ch, organizationID, subscription)
+		return nil
+	})
+}
+
+func (sc *subscriptionCollector) collectSubscription(ch chan<- prometheus.Metric, organizationID string, subscription *shared.Subscription) {
+	ch <- prometheus.MustNewConstMetric(
+		sc.info,
+		prometheus.GaugeValue,
+		1.0,
+		organizationID,
+		subscription.Plan,
+	)
+
+	quotas := []struct {
+		resource string
+		quota    shared.SubscriptionQuota
+		scale    float64
+	}{
+		{"cpu_cores", subscription.Quotas.CPU, 1},
+		{"environments", subscription.Quotas.Environments, 1},
+		{"memory_bytes", subscription.Quotas.Memory, float64(internal.MiBToBytes(1))},
+		{"storage_bytes", subscription.Quotas.Storage, float64(internal.GiBToBytes(1))},
+	}
+
+	for _, q := range quotas {
+		ch <- prometheus.MustNewConstMetric(
+			sc.quotaLimit,
+			prometheus.GaugeValue,
+			q.quota.Limit*q.scale,
+			organizationID,
+			q.resource,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			sc.quotaUsed,
+			prometheus.GaugeValue,
+			q.quota.Used*q.scale,
+			organizationID,
+			q.resource,
+		)
+
+		if q.quota.Limit > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				sc.quotaUtilization,
+				prometheus.GaugeValue,
+				q.quota.Used/q.quota.Limit,
+				organizationID,
+				q.resource,
+			)
+		}
+	}
+}
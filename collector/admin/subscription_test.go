@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestSubscriptionCollector(t *testing.T) {
+	projectProvider := staticProjects{
+		{ProjectID: "acme", OrganizationId: "acme"},
+		{ProjectID: "acme-prd", OrganizationId: "acme"},
+		{ProjectID: "acme-dev", OrganizationId: "acme"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/organizations/acme/subscription", r.URL.Path)
+		_, err := fmt.Fprintln(w, `{
+			"plan": "enterprise",
+			"quotas": {
+				"cpu": {"limit": 16, "used": 12},
+				"environments": {"limit": 4, "used": 3},
+				"memory": {"limit": 32768, "used": 8192},
+				"storage": {"limit": 100, "used": 0}
+			}
+		}`)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewSubscriptionCollector(client, projectProvider, 2)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	assert.Contains(t, output, `lcp_api_subscription_info{organization_id="acme",plan="enterprise"} 1`)
+	assert.Contains(t, output, `lcp_api_subscription_quota_limit{organization_id="acme",resource="cpu_cores"} 16`)
+	assert.Contains(t, output, `lcp_api_subscription_quota_used{organization_id="acme",resource="cpu_cores"} 12`)
+	assert.Contains(t, output, `lcp_api_subscription_quota_utilization_ratio{organization_id="acme",resource="cpu_cores"} 0.75`)
+	assert.Contains(t, output, `lcp_api_subscription_quota_limit{organization_id="acme",resource="memory_bytes"} 3.4359738368e+10`)
+	assert.Contains(t, output, `lcp_api_subscription_quota_utilization_ratio{organization_id="acme",resource="memory_bytes"} 0.25`)
+	assert.Contains(t, output, `lcp_api_subscription_quota_limit{organization_id="acme",resource="storage_bytes"} 1.073741824e+11`)
+	assert.Contains(t, output, `lcp_api_subscription_quota_used{organization_id="acme",resource="environments"} 3`)
+}
+
+func TestSubscriptionCollector_LogsFailedOrganization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	out := logrus.StandardLogger().Out
+	logrus.SetOutput(&logs)
+	defer logrus.SetOutput(out)
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewSubscriptionCollector(client, staticProjects{{ProjectID: "acme-prd", OrganizationId: "acme"}}, 1)
+
+	ch := make(chan prometheus.Metric, 10)
+	require.Error(t, collector.Update(t.Context(), ch))
+	assert.Contains(t, logs.String(), "Failed to collect organization acme")
+}
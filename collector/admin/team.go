@@ -0,0 +1,134 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+var teamRoles = []string{"admin", "contributor", "guest", "owner"}
+
+type teamCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	concurrency     int
+
+	members            *prometheus.Desc
+	pendingInvitations *prometheus.Desc
+	withoutAdmin       *prometheus.Desc
+}
+
+func NewTeamCollector(client *lcp.Client, provider ProjectProvider, concurrency int) *teamCollector {
+	fqName := internal.Name("team")
+
+	return &teamCollector{
+		client:          client,
+		projectProvider: provider,
+		concurrency:     concurrency,
+		members: prometheus.NewDesc(
+			fqName("members"),
+			"Number of team members per role and project",
+			[]string{"project_name", "role"},
+			nil,
+		),
+		pendingInvitations: prometheus.NewDesc(
+			fqName("pending_invitations"),
+			"Number of pending team invitations per project",
+			[]string{"project_name"},
+			nil,
+		),
+		withoutAdmin: prometheus.NewDesc(
+			fqName("without_admin"),
+			"Whether the project has no owner or admin. 1 if none, 0 otherwise",
+			[]string{"project_name"},
+			nil,
+		),
+	}
+}
+
+func (tc *teamCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tc.members
+	ch <- tc.pendingInvitations
+	ch <- tc.withoutAdmin
+}
+
+func (tc *teamCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = tc.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (tc *teamCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := tc.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("TeamCollector", "No projects found")
+		return nil
+	}
+
+	return forEachProject(ctx, "TeamCollector", projects, tc.concurrency, ch, func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error {
+		projectPath := fmt.Sprintf("/projects/%s", url.PathEscape(projectID))
+
+		members, err := lcp.FetchFrom[shared.TeamMember](ctx, tc.client, projectPath+"/members", nil)
+		if err != nil {
+			return fmt.Errorf("members: %w", err)
+		}
+
+		invitations, err := lcp.FetchFrom[shared.TeamInvitation](ctx, tc.client, projectPath+"/invitations", nil)
+		if err != nil {
+			return fmt.Errorf("invitations: %w", err)
+		}
+
+		tc.collectTeam(ch, projectID, members, invitations)
+		return nil
+	})
+}
+
+func (tc *teamCollector) collectTeam(ch chan<- prometheus.Metric, projectID string, members []shared.TeamMember, invitations []shared.TeamInvitation) {
+	counts := make(map[string]int, len(teamRoles))
+	for _, role := range teamRoles {
+		counts[role] = 0
+	}
+	for _, member := range members {
+		counts[strings.ToLower(member.Role)]++
+	}
+
+	for role, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			tc.members,
+			prometheus.GaugeValue,
+			float64(count),
+			projectID,
+			role,
+		)
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		tc.pendingInvitations,
+		prometheus.GaugeValue,
+		float64(len(invitations)),
+		projectID,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		tc.withoutAdmin,
+		prometheus.GaugeValue,
+		internal.BoolToFloat(counts["owner"]+counts["admin"] == 0),
+		projectID,
+	)
+}
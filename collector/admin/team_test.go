@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestTeamCollector(t *testing.T) {
+	projectProvider := staticProjects{
+		{ProjectID: "proj-prd", OrganizationId: "proj"},
+		{ProjectID: "proj-dev", OrganizationId: "proj"},
+	}
+
+	responses := map[string]string{
+		"/projects/proj-prd/members": `[
+			{"email": "owner@acme.com", "role": "OWNER"},
+			{"email": "dev1@acme.com", "role": "contributor"},
+			{"email": "dev2@acme.com", "role": "contributor"},
+			{"email": "auditor@acme.com", "role": "viewer"}
+		]`,
+		"/projects/proj-prd/invitations": `[{"email": "new@acme.com", "role": "guest", "createdAt": 1740916800000}]`,
+		"/projects/proj-dev/members":     `[{"email": "dev1@acme.com", "role": "contributor"}]`,
+		"/projects/proj-dev/invitations": `[]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := responses[r.URL.Path]
+		require.True(t, ok, "unexpected path %s", r.URL.Path)
+		_, err := fmt.Fprintln(w, payload)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewTeamCollector(client, projectProvider, 2)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	assert.Contains(t, output, `lcp_api_team_members{project_name="proj-prd",role="owner"} 1`)
+	assert.Contains(t, output, `lcp_api_team_members{project_name="proj-prd",role="contributor"} 2`)
+	assert.Contains(t, output, `lcp_api_team_members{project_name="proj-prd",role="viewer"} 1`)
+	assert.Contains(t, output, `lcp_api_team_members{project_name="proj-prd",role="admin"} 0`)
+	assert.Contains(t, output, `lcp_api_team_pending_invitations{project_name="proj-prd"} 1`)
+	assert.Contains(t, output, `lcp_api_team_pending_invitations{project_name="proj-dev"} 0`)
+	assert.Contains(t, output, `lcp_api_team_without_admin{project_name="proj-prd"} 0`)
+	assert.Contains(t, output, `lcp_api_team_without_admin{project_name="proj-dev"} 1`)
+	assert.NotContains(t, output, `acme.com`)
+}
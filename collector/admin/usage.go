@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/shared"
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+type usageCollector struct {
+	client          *lcp.Client
+	projectProvider ProjectProvider
+	interval        time.Duration
+	concurrency     int
+	now             func() time.Time
+
+	cpuCores             *prometheus.Desc
+	memoryBytes          *prometheus.Desc
+	networkReceiveBytes  *prometheus.Desc
+	networkTransmitBytes *prometheus.Desc
+}
+
+// NewUsageCollector queries usage over the last complete interval, so the
+// interval should match how often the collector is refreshed. Collectors
+// refreshed on every scrape fall back to one minute windows.
+func NewUsageCollector(client *lcp.Client, provider ProjectProvider, interval time.Duration, concurrency int) *usageCollector {
+	fqName := internal.Name("usage")
+	labels := []string{"project_name", "service_id"}
+
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &usageCollector{
+		client:          client,
+		projectProvider: provider,
+		interval:        interval,
+		concurrency:     concurrency,
+		now:             time.Now,
+		cpuCores: prometheus.NewDesc(
+			fqName("cpu_cores"),
+			"Average CPU cores used by the service over the query window",
+			labels,
+			nil,
+		),
+		memoryBytes: prometheus.NewDesc(
+			fqName("memory_bytes"),
+			"Average memory used by the service over the query window in bytes",
+			labels,
+			nil,
+		),
+		networkReceiveBytes: prometheus.NewDesc(
+			fqName("network_receive_bytes"),
+			"Network bytes received by the service over the query window",
+			labels,
+			nil,
+		),
+		networkTransmitBytes: prometheus.NewDesc(
+			fqName("network_transmit_bytes"),
+			"Network bytes transmitted by the service over the query window",
+			labels,
+			nil,
+		),
+	}
+}
+
+func (uc *usageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- uc.cpuCores
+	ch <- uc.memoryBytes
+	ch <- uc.networkReceiveBytes
+	ch <- uc.networkTransmitBytes
+}
+
+func (uc *usageCollector) Collect(ch chan<- prometheus.Metric) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = uc.Update(context.Background(), ch)
+	}()
+	wg.Wait()
+}
+
+func (uc *usageCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	projects, err := uc.projectProvider.Projects(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(projects) == 0 {
+		internal.LogWarn("UsageCollector", "No projects found")
+		return nil
+	}
+
+	dataRange := internal.IntervalRange(uc.now(), uc.interval)
+	queryParams := map[string]string{
+		"start": dataRange.From,
+		"end":   dataRange.End,
+	}
+
+	return forEachProject(ctx, "UsageCollector", projects, uc.concurrency, ch, func(ctx context.Context, projectID string, ch chan<- prometheus.Metric) error {
+		services, err := fetchServices(ctx, uc.client, projectID)
+		if err != nil {
+			return fmt.Errorf("services: %w", err)
+		}
+
+		for _, service := range services {
+			path := fmt.Sprintf("/projects/%s/services/%s/usage", url.PathEscape(projectID), url.PathEscape(service.ServiceID))
+			usage, err := lcp.FetchOneFrom[shared.ServiceUsage](ctx, uc.client, path, queryParams)
+			if err != nil {
+				return fmt.Errorf("usage of service %s: %w", service.ServiceID, err)
+			}
+
+			uc.collectUsage(ch, projectID, service.ServiceID, usage)
+		}
+		return nil
+	})
+}
+
+func (uc *usageCollector) collectUsage(ch chan<- prometheus.Metric, projectID, serviceID string, usage *shared.ServiceUsage) {
+	ch <- prometheus.MustNewConstMetric(
+		uc.cpuCores,
+		prometheus.GaugeValue,
+		usage.CPU,
+		projectID,
+		serviceID,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		uc.memoryBytes,
+		prometheus.GaugeValue,
+		float64(usage.Memory),
+		projectID,
+		serviceID,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		uc.networkReceiveBytes,
+		prometheus.GaugeValue,
+		float64(usage.NetworkReceiveBytes),
+		projectID,
+		serviceID,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		uc.networkTransmitBytes,
+		prometheus.GaugeValue,
+		float64(usage.NetworkTransmitBytes),
+		projectID,
+		serviceID,
+	)
+}
@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/lcp"
+)
+
+func TestUsageCollector(t *testing.T) {
+	projectProvider := staticProjects{{ProjectID: "proj-prd", OrganizationId: "proj"}}
+
+	responses := map[string]string{
+		"/projects/proj-prd/services":                 `[{"serviceId": "liferay"}, {"serviceId": "webserver"}]`,
+		"/projects/proj-prd/services/liferay/usage":   `{"cpu": 1.5, "memory": 6442450944, "networkReceiveBytes": 1048576, "networkTransmitBytes": 4194304}`,
+		"/projects/proj-prd/services/webserver/usage": `{"cpu": 0.25, "memory": 268435456, "networkReceiveBytes": 2048, "networkTransmitBytes": 8192}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := responses[r.URL.Path]
+		require.True(t, ok, "unexpected path %s", r.URL.Path)
+
+		if r.URL.Path != "/projects/proj-prd/services" {
+			require.Equal(t, "2025-03-02T17:55:00Z", r.URL.Query().Get("start"))
+			require.Equal(t, "2025-03-02T18:00:00Z", r.URL.Query().Get("end"))
+		}
+
+		_, err := fmt.Fprintln(w, payload)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := lcp.NewClient(server.URL, "fake-token")
+	collector := NewUsageCollector(client, projectProvider, 5*time.Minute, 1)
+	collector.now = func() time.Time { return time.Date(2025, 3, 2, 18, 3, 20, 0, time.UTC) }
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(collector))
+
+	serverMetrics := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer serverMetrics.Close()
+
+	resp, err := http.Get(serverMetrics.URL)
+	require.NoError(t, err)
+	defer func() {
+		closeErr := resp.Body.Close()
+		require.NoError(t, closeErr)
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	output := string(body)
+
+	assert.Contains(t, output, `lcp_api_usage_cpu_cores{project_name="proj-prd",service_id="liferay"} 1.5`)
+	assert.Contains(t, output, `lcp_api_usage_cpu_cores{project_name="proj-prd",service_id="webserver"} 0.25`)
+	assert.Contains(t, output, `lcp_api_usage_memory_bytes{project_name="proj-prd",service_id="liferay"} 6.442450944e+09`)
+	assert.Contains(t, output, `lcp_api_usage_network_receive_bytes{project_name="proj-prd",service_id="liferay"} 1.048576e+06`)
+	assert.Contains(t, output, `lcp_api_usage_network_transmit_bytes{project_name="proj-prd",service_id="webserver"} 8192`)
+}
+
+func TestNewUsageCollector_DefaultsWindow(t *testing.T) {
+	collector := NewUsageCollector(lcp.NewClient("http://unused", "fake-token"), staticProjects{}, 0, 1)
+	assert.Equal(t, time.Minute, collector.interval)
+}
@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -38,20 +40,20 @@ func (c *infoCollector) Collect(ch chan<- prometheus.Metric) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		c.collectMetrics(ch)
+		_ = c.Update(context.Background(), ch)
 	}()
 	wg.Wait()
 }
 
-func (c *infoCollector) collectMetrics(ch chan<- prometheus.Metric) {
-	info, err := lcp.FetchFrom[shared.Info](c.client, "/", nil)
+func (c *infoCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	info, err := lcp.FetchFrom[shared.Info](ctx, c.client, "/", nil)
 	if err != nil {
 		internal.LogError("InfoCollector", "Failed to fetch status info: %v", err)
-		return
+		return err
 	}
 	if len(info) == 0 {
 		internal.LogWarn("InfoCollector", "No status info returned from API")
-		return
+		return fmt.Errorf("no status info returned")
 	}
 
 	data := info[0]
@@ -63,4 +65,6 @@ func (c *infoCollector) collectMetrics(ch chan<- prometheus.Metric) {
 		data.Domains.Infrastructure,
 		data.Domains.Service,
 	)
+
+	return nil
 }
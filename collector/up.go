@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -38,20 +40,20 @@ func (c *upCollector) Collect(ch chan<- prometheus.Metric) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		c.collectMetrics(ch)
+		_ = c.Update(context.Background(), ch)
 	}()
 	wg.Wait()
 }
 
-func (c *upCollector) collectMetrics(ch chan<- prometheus.Metric) {
-	health, err := lcp.FetchFrom[shared.HealthCheck](c.client, "/health-check", nil)
+func (c *upCollector) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	health, err := lcp.FetchFrom[shared.HealthCheck](ctx, c.client, "/health-check", nil)
 	if err != nil {
 		internal.LogError("UpCollector", "Failed to fetch health check: %v", err)
-		return
+		return err
 	}
 	if len(health) == 0 {
 		internal.LogWarn("UpCollector", "No health check data returned")
-		return
+		return fmt.Errorf("no health check data returned")
 	}
 
 	status := health[0].Status
@@ -68,4 +70,6 @@ func (c *upCollector) collectMetrics(ch chan<- prometheus.Metric) {
 		value,
 		status,
 	)
+
+	return nil
 }
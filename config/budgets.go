@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Budgets holds monthly autoscale budgets by project ID. The budget of a root
+// project, from its entry or the default, covers all its child projects
+// together; a child project only has a budget of its own from an explicit entry.
+type Budgets struct {
+	Currency string             `yaml:"currency"`
+	Default  float64            `yaml:"default"`
+	Projects map[string]float64 `yaml:"projects"`
+}
+
+func LoadBudgets(path string) (*Budgets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budgets file: %w", err)
+	}
+
+	var budgets Budgets
+	if err := yaml.Unmarshal(data, &budgets); err != nil {
+		return nil, fmt.Errorf("failed to parse budgets file: %w", err)
+	}
+
+	if budgets.Default < 0 {
+		return nil, fmt.Errorf("invalid default budget: must be non-negative, got %v", budgets.Default)
+	}
+	for projectID, amount := range budgets.Projects {
+		if amount < 0 {
+			return nil, fmt.Errorf("invalid budget for project %s: must be non-negative, got %v", projectID, amount)
+		}
+	}
+
+	return &budgets, nil
+}
+
+func (b *Budgets) For(projectID string, root bool) (float64, bool) {
+	if b == nil {
+		return 0, false
+	}
+
+	if amount, ok := b.Projects[projectID]; ok {
+		return amount, amount > 0
+	}
+
+	if !root {
+		return 0, false
+	}
+
+	return b.Default, b.Default > 0
+}
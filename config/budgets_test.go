@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "file.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadBudgets(t *testing.T) {
+	path := writeFile(t, `
+currency: USD
+default: 100
+projects:
+  proj-1: 250
+  proj-2-prd: 50
+`)
+
+	budgets, err := LoadBudgets(path)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", budgets.Currency)
+
+	tests := []struct {
+		name      string
+		projectID string
+		root      bool
+		expected  float64
+		ok        bool
+	}{
+		{"explicit child project", "proj-2-prd", false, 50, true},
+		{"explicit root project", "proj-1", true, 250, true},
+		{"default for root project", "proj-3", true, 100, true},
+		{"no default for child project", "proj-3-prd", false, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, ok := budgets.For(tt.projectID, tt.root)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+}
+
+func TestLoadBudgets_WithoutDefault(t *testing.T) {
+	budgets, err := LoadBudgets(writeFile(t, "projects:\n  proj-1: 10\n"))
+	require.NoError(t, err)
+
+	_, ok := budgets.For("proj-2", true)
+	assert.False(t, ok)
+
+	_, ok = budgets.For("proj-1-uat", false)
+	assert.False(t, ok, "children of a budgeted root project share its budget")
+
+	var nilBudgets *Budgets
+	_, ok = nilBudgets.For("proj-1", true)
+	assert.False(t, ok)
+}
+
+func TestLoadBudgets_Invalid(t *testing.T) {
+	_, err := LoadBudgets(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+
+	_, err = LoadBudgets(writeFile(t, "projects: [1, 2"))
+	assert.Error(t, err)
+
+	_, err = LoadBudgets(writeFile(t, "projects:\n  proj-1: -5\n"))
+	assert.Error(t, err)
+}
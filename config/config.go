@@ -10,35 +10,132 @@ import (
 )
 
 type Config struct {
+	ActivitiesCursorFile          string
+	ActivitiesInterval            time.Duration
+	ActivitiesLog                 bool
+	AlertsInterval                time.Duration
+	AlertsWindow                  internal.DateWindowMode
+	AutoscaleBatchSize            int
+	AutoscaleBudgetsFile          string
+	AutoscaleConcurrency          int
+	AutoscaleInterval             time.Duration
+	AutoscaleWindow               internal.DateWindowMode
+	BackupsInterval               time.Duration
+	ClusterDiscoveryInterval      time.Duration
+	DeploymentsInterval           time.Duration
+	DeploymentsWindow             internal.DateWindowMode
+	CustomDomainsInterval         time.Duration
 	Duration                      time.Duration
+	EnableActivitiesMetrics       bool
+	EnableAlertsMetrics           bool
+	EnableAutoscaleBillingPeriod  bool
+	EnableAutoscaleLegacyHistory  bool
+	EnableBackupsMetrics          bool
 	EnableClusterDiscoveryMetrics bool
+	EnableCustomDomainsMetrics    bool
+	EnableDeploymentsMetrics      bool
+	EnableEnvironmentMetrics      bool
 	EnableGoMetrics               bool
 	EnableProcessMetrics          bool
 	EnableProjectsMetrics         bool
 	EnablePromHttpMetrics         bool
+	EnableServicesMetrics         bool
+	EnableSubscriptionMetrics     bool
+	EnableTeamMetrics             bool
+	EnableUsageMetrics            bool
 	EnableAutoscaleMetrics        bool
 	Endpoint                      string
+	EnvironmentInterval           time.Duration
+	EventsCursorFile              string
+	EventsLog                     bool
+	EventsWebhookURL              string
+	ExchangeRatesFile             string
+	ExchangeRatesReloadInterval   time.Duration
+	InfoInterval                  time.Duration
+	InventoryTTL                  time.Duration
 	LogFormat                     string
 	LogLevel                      string
+	MaxRetries                    int
 	MetricsPath                   string
 	Port                          string
+	ProjectConcurrency            int
+	ProjectFilterFile             string
+	ProjectsInterval              time.Duration
+	RequestTimeout                time.Duration
+	RetryBaseDelay                time.Duration
+	RetryMaxDelay                 time.Duration
+	ScrapeTimeoutOffset           time.Duration
+	ServicesInterval              time.Duration
+	SubscriptionInterval          time.Duration
+	TeamInterval                  time.Duration
 	Token                         string
+	UpInterval                    time.Duration
+	UsageInterval                 time.Duration
 }
 
 func ParseFlags() *Config {
 	var cfg Config
+	var alertsWindow, autoscaleWindow, deploymentsWindow string
 
+	flag.BoolVar(&cfg.EnableAutoscaleLegacyHistory, "enable-autoscale-legacy-history", false, "Enable legacy per-event autoscale history metrics (high cardinality)")
 	flag.BoolVar(&cfg.EnableClusterDiscoveryMetrics, "enable-cluster-discovery-metrics", true, "Enable cluster discovery metrics")
 	flag.BoolVar(&cfg.EnableAutoscaleMetrics, "enable-autoscale-metrics", true, "Enable autoscale metrics")
+	flag.BoolVar(&cfg.EnableAutoscaleBillingPeriod, "enable-autoscale-billing-period", false, "Enable autoscale cost metrics for the current and previous billing month")
 	flag.BoolVar(&cfg.EnableGoMetrics, "enable-go-metrics", false, "Enable Go default metrics")
 	flag.BoolVar(&cfg.EnableProcessMetrics, "enable-process-metrics", false, "Enable process metrics")
 	flag.BoolVar(&cfg.EnablePromHttpMetrics, "enable-promhttp-metrics", false, "Enable promhttp metrics")
+	flag.BoolVar(&cfg.EnableActivitiesMetrics, "enable-activities-metrics", false, "Enable activity feed metrics (one request per project)")
+	flag.BoolVar(&cfg.EnableAlertsMetrics, "enable-alerts-metrics", false, "Enable platform alert metrics (two requests per project)")
+	flag.BoolVar(&cfg.EnableBackupsMetrics, "enable-backups-metrics", false, "Enable backup metrics (two requests per project)")
+	flag.BoolVar(&cfg.EnableCustomDomainsMetrics, "enable-custom-domains-metrics", false, "Enable custom domain and certificate metrics (one request per project)")
+	flag.BoolVar(&cfg.EnableDeploymentsMetrics, "enable-deployments-metrics", false, "Enable deployment and build metrics (four requests per project)")
+	flag.BoolVar(&cfg.EnableEnvironmentMetrics, "enable-environment-metrics", false, "Enable environment variable and secret hygiene metrics (values are never read)")
+	flag.BoolVar(&cfg.EnableUsageMetrics, "enable-usage-metrics", false, "Enable per-service resource usage metrics (one request per service)")
+	flag.BoolVar(&cfg.EnableSubscriptionMetrics, "enable-subscription-metrics", false, "Enable subscription plan and quota metrics (one request per organization)")
+	flag.BoolVar(&cfg.EnableTeamMetrics, "enable-team-metrics", false, "Enable team member and invitation metrics (two requests per project)")
+	flag.BoolVar(&cfg.EnableServicesMetrics, "enable-services-metrics", false, "Enable per-service metrics (one request per project)")
 	flag.DurationVar(&cfg.Duration, "duration", 0, "Duration to shift from now (e.g. 24h, -48h)")
+	flag.IntVar(&cfg.AutoscaleBatchSize, "autoscale-batch-size", 50, "Maximum number of root projects per autoscale report request (0 disables batching)")
+	flag.IntVar(&cfg.AutoscaleConcurrency, "autoscale-concurrency", 4, "Maximum number of concurrent autoscale report requests")
+	flag.StringVar(&cfg.AutoscaleBudgetsFile, "autoscale-budgets-file", "", "Path to a YAML file with monthly autoscale budgets: currency, default (per root project) and projects (project ID to amount; a root project budget covers all its children), see README")
+	flag.StringVar(&autoscaleWindow, "autoscale-window", "last", "Report window of the autoscale collector: last (-duration back from now), day (calendar day to date) or month (billing month to date)")
 	flag.StringVar(&cfg.Endpoint, "endpoint", "", "Base endpoint for the REST API")
+	flag.StringVar(&cfg.ExchangeRatesFile, "exchange-rates-file", "", "Path to a YAML file with exchange rates used to normalize autoscale costs: target currency and rates (1 unit of each listed currency = rate units of target), see README")
+	flag.DurationVar(&cfg.ExchangeRatesReloadInterval, "exchange-rates-reload-interval", time.Minute, "Interval to check the exchange rates file for changes")
+	flag.StringVar(&cfg.EventsCursorFile, "events-cursor-file", "", "Path to a JSON file persisting the newest autoscale event sent per sink, so restarts do not resend the window (empty keeps it in memory and events may be resent after a restart)")
+	flag.BoolVar(&cfg.EventsLog, "events-log", false, "Emit new autoscale events as JSON lines on stdout")
+	flag.StringVar(&cfg.EventsWebhookURL, "events-webhook-url", "", "URL receiving new autoscale events as JSON POST requests")
 	flag.StringVar(&cfg.LogFormat, "log-format", "json", "Log format (json or text)")
 	flag.StringVar(&cfg.LogLevel, "log-level", "info", "Log level (debug, info, warn, error)")
 	flag.StringVar(&cfg.MetricsPath, "metrics-path", "/metrics", "Path for the metrics endpoint")
 	flag.StringVar(&cfg.Port, "port", "9103", "Port for the HTTP server")
+	flag.DurationVar(&cfg.AutoscaleInterval, "autoscale-interval", 5*time.Minute, "Refresh interval of the autoscale collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.ClusterDiscoveryInterval, "cluster-discovery-interval", 5*time.Minute, "Refresh interval of the cluster discovery collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.InfoInterval, "info-interval", time.Minute, "Refresh interval of the info collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.InventoryTTL, "inventory-ttl", 5*time.Minute, "How long the shared project inventory is reused before it is fetched again (0 fetches on every use)")
+	flag.StringVar(&cfg.ProjectFilterFile, "project-filter-file", "", "Path to a YAML file with include/exclude rules (clusters, envTypes, organizationIds, projectId regex, trial) applied to the project inventory, see README")
+	flag.IntVar(&cfg.ProjectConcurrency, "project-concurrency", 4, "Maximum number of projects queried in parallel by per-project collectors")
+	flag.DurationVar(&cfg.ActivitiesInterval, "activities-interval", time.Minute, "Refresh interval of the activities collector (0 collects on every scrape)")
+	flag.StringVar(&cfg.ActivitiesCursorFile, "activities-cursor-file", "", "Path to a JSON file persisting the last activity read per project (empty keeps it in memory)")
+	flag.BoolVar(&cfg.ActivitiesLog, "activities-log", false, "Emit new activities as JSON lines on stdout")
+	flag.StringVar(&alertsWindow, "alerts-window", "last", "Window of the raised alerts count: last (-duration back from now), day (calendar day to date) or month (billing month to date)")
+	flag.DurationVar(&cfg.AlertsInterval, "alerts-interval", time.Minute, "Refresh interval of the alerts collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.BackupsInterval, "backups-interval", 15*time.Minute, "Refresh interval of the backups collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.CustomDomainsInterval, "custom-domains-interval", 15*time.Minute, "Refresh interval of the custom domains collector (0 collects on every scrape)")
+	flag.StringVar(&deploymentsWindow, "deployments-window", "last", "Window of the failed deployments count and build durations: last (-duration back from now), day (calendar day to date) or month (billing month to date)")
+	flag.DurationVar(&cfg.DeploymentsInterval, "deployments-interval", 5*time.Minute, "Refresh interval of the deployments collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.EnvironmentInterval, "environment-interval", 15*time.Minute, "Refresh interval of the environment collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.UsageInterval, "usage-interval", time.Minute, "Refresh interval of the usage collector, also used as its query window (0 collects on every scrape over one minute windows)")
+	flag.DurationVar(&cfg.SubscriptionInterval, "subscription-interval", 15*time.Minute, "Refresh interval of the subscription collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.TeamInterval, "team-interval", time.Hour, "Refresh interval of the team collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.ServicesInterval, "services-interval", 5*time.Minute, "Refresh interval of the services collector (0 collects on every scrape)")
+	flag.DurationVar(&cfg.ProjectsInterval, "projects-interval", 5*time.Minute, "Refresh interval of the projects collector (0 collects on every scrape)")
+	flag.IntVar(&cfg.MaxRetries, "max-retries", 3, "Maximum number of retries for failed LCP API requests")
+	flag.DurationVar(&cfg.RequestTimeout, "request-timeout", 0, "Timeout of a single LCP API request attempt (0 bounds requests only by the refresh interval or scrape timeout)")
+	flag.DurationVar(&cfg.RetryBaseDelay, "retry-base-delay", 500*time.Millisecond, "Base delay of the exponential backoff between retries")
+	flag.DurationVar(&cfg.RetryMaxDelay, "retry-max-delay", 10*time.Second, "Maximum delay between retries, also caps Retry-After")
+	flag.DurationVar(&cfg.ScrapeTimeoutOffset, "scrape-timeout-offset", 500*time.Millisecond, "Offset subtracted from the Prometheus scrape timeout to bound LCP API calls")
+	flag.DurationVar(&cfg.UpInterval, "up-interval", 30*time.Second, "Refresh interval of the up collector (0 collects on every scrape)")
 
 	flag.Parse()
 
@@ -63,6 +160,72 @@ func ParseFlags() *Config {
 		internal.LogFatal("Config", "Invalid duration: must be non-negative, got %s", cfg.Duration.String())
 	}
 
+	window, err := internal.ParseDateWindowMode(autoscaleWindow)
+	if err != nil {
+		internal.LogFatal("Config", "Invalid autoscale-window: %v", err)
+	}
+	cfg.AutoscaleWindow = window
+
+	window, err = internal.ParseDateWindowMode(deploymentsWindow)
+	if err != nil {
+		internal.LogFatal("Config", "Invalid deployments-window: %v", err)
+	}
+	cfg.DeploymentsWindow = window
+
+	window, err = internal.ParseDateWindowMode(alertsWindow)
+	if err != nil {
+		internal.LogFatal("Config", "Invalid alerts-window: %v", err)
+	}
+	cfg.AlertsWindow = window
+
+	intervals := map[string]time.Duration{
+		"activities-interval":        cfg.ActivitiesInterval,
+		"alerts-interval":            cfg.AlertsInterval,
+		"autoscale-interval":         cfg.AutoscaleInterval,
+		"backups-interval":           cfg.BackupsInterval,
+		"cluster-discovery-interval": cfg.ClusterDiscoveryInterval,
+		"custom-domains-interval":    cfg.CustomDomainsInterval,
+		"deployments-interval":       cfg.DeploymentsInterval,
+		"info-interval":              cfg.InfoInterval,
+		"environment-interval":       cfg.EnvironmentInterval,
+		"inventory-ttl":              cfg.InventoryTTL,
+		"projects-interval":          cfg.ProjectsInterval,
+		"services-interval":          cfg.ServicesInterval,
+		"subscription-interval":      cfg.SubscriptionInterval,
+		"team-interval":              cfg.TeamInterval,
+		"up-interval":                cfg.UpInterval,
+		"usage-interval":             cfg.UsageInterval,
+	}
+	for name, interval := range intervals {
+		if interval < 0 {
+			internal.LogFatal("Config", "Invalid %s: must be non-negative, got %s", name, interval.String())
+		}
+	}
+
+	if cfg.RequestTimeout < 0 {
+		internal.LogFatal("Config", "Invalid request-timeout: must be non-negative, got %s", cfg.RequestTimeout.String())
+	}
+
+	if cfg.AutoscaleBatchSize < 0 {
+		internal.LogFatal("Config", "Invalid autoscale-batch-size: must be non-negative, got %d", cfg.AutoscaleBatchSize)
+	}
+
+	if cfg.AutoscaleConcurrency < 1 {
+		internal.LogFatal("Config", "Invalid autoscale-concurrency: must be at least 1, got %d", cfg.AutoscaleConcurrency)
+	}
+
+	if cfg.ProjectConcurrency < 1 {
+		internal.LogFatal("Config", "Invalid project-concurrency: must be at least 1, got %d", cfg.ProjectConcurrency)
+	}
+
+	if cfg.ExchangeRatesReloadInterval <= 0 {
+		internal.LogFatal("Config", "Invalid exchange-rates-reload-interval: must be positive, got %s", cfg.ExchangeRatesReloadInterval.String())
+	}
+
+	if cfg.MaxRetries < 0 {
+		internal.LogFatal("Config", "Invalid max-retries: must be non-negative, got %d", cfg.MaxRetries)
+	}
+
 	level, err := logrus.ParseLevel(cfg.LogLevel)
 	if err != nil {
 		internal.LogFatal("Config", "Invalid log level: %v", err)
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jullianow/lcp-exporter/internal"
+)
+
+// ExchangeRates maps a source currency to the number of units of Target that
+// one unit of it is worth: with target USD, "EUR: 1.1" means 1 EUR = 1.1 USD.
+type ExchangeRates struct {
+	Target string             `yaml:"target"`
+	Rates  map[string]float64 `yaml:"rates"`
+}
+
+func ParseExchangeRates(data []byte) (*ExchangeRates, error) {
+	var raw ExchangeRates
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+
+	if raw.Target == "" {
+		return nil, fmt.Errorf("exchange rates target currency is required")
+	}
+
+	rates := &ExchangeRates{
+		Target: strings.ToUpper(raw.Target),
+		Rates:  make(map[string]float64, len(raw.Rates)),
+	}
+	for currency, rate := range raw.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for %s: must be positive, got %v", currency, rate)
+		}
+		rates.Rates[strings.ToUpper(currency)] = rate
+	}
+
+	return rates, nil
+}
+
+// Convert returns amount, given in currency, expressed in the target currency.
+func (r *ExchangeRates) Convert(amount float64, currency string) (float64, bool) {
+	currency = strings.ToUpper(currency)
+	if currency == r.Target {
+		return amount, true
+	}
+
+	rate, ok := r.Rates[currency]
+	if !ok {
+		return 0, false
+	}
+	return amount * rate, true
+}
+
+type ExchangeRatesFile struct {
+	path string
+
+	mu      sync.RWMutex
+	rates   *ExchangeRates
+	modTime time.Time
+}
+
+func NewExchangeRatesFile(path string) (*ExchangeRatesFile, error) {
+	f := &ExchangeRatesFile{path: path}
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *ExchangeRatesFile) Rates() *ExchangeRates {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.rates
+}
+
+func (f *ExchangeRatesFile) Reload() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat exchange rates file: %w", err)
+	}
+
+	f.mu.RLock()
+	unchanged := f.rates != nil && info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read exchange rates file: %w", err)
+	}
+
+	rates, err := ParseExchangeRates(data)
+	if err != nil {
+		return false, err
+	}
+
+	f.mu.Lock()
+	f.rates = rates
+	f.modTime = info.ModTime()
+	f.mu.Unlock()
+
+	return true, nil
+}
+
+func (f *ExchangeRatesFile) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := f.Reload()
+			if err != nil {
+				internal.LogError("ExchangeRates", "Failed to reload %s, keeping previous rates: %v", f.path, err)
+				continue
+			}
+			if reloaded {
+				internal.LogInfo("ExchangeRates", "Reloaded exchange rates from %s", f.path)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExchangeRates(t *testing.T) {
+	rates, err := ParseExchangeRates([]byte("target: usd\nrates:\n  eur: 1.1\n  BRL: 0.2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "USD", rates.Target)
+
+	tests := []struct {
+		amount   float64
+		currency string
+		expected float64
+		ok       bool
+	}{
+		{10, "USD", 10, true},
+		{10, "usd", 10, true},
+		{10, "EUR", 11, true},
+		{10, "BRL", 2, true},
+		{10, "JPY", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			converted, ok := rates.Convert(tt.amount, tt.currency)
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.expected, converted, 1e-9)
+		})
+	}
+}
+
+func TestParseExchangeRates_Invalid(t *testing.T) {
+	_, err := ParseExchangeRates([]byte("rates:\n  EUR: 1.1\n"))
+	assert.Error(t, err)
+
+	_, err = ParseExchangeRates([]byte("target: USD\nrates:\n  EUR: 0\n"))
+	assert.Error(t, err)
+
+	_, err = ParseExchangeRates([]byte("target: ["))
+	assert.Error(t, err)
+}
+
+func TestExchangeRatesFile_Reload(t *testing.T) {
+	path := writeFile(t, "target: USD\nrates:\n  EUR: 1.1\n")
+
+	file, err := NewExchangeRatesFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1.1, file.Rates().Rates["EUR"])
+
+	reloaded, err := file.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	require.NoError(t, os.WriteFile(path, []byte("target: USD\nrates:\n  EUR: 1.2\n"), 0o600))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, future, future))
+
+	reloaded, err = file.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, 1.2, file.Rates().Rates["EUR"])
+
+	require.NoError(t, os.WriteFile(path, []byte("target: ["), 0o600))
+	later := future.Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	_, err = file.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, 1.2, file.Rates().Rates["EUR"])
+}
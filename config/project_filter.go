@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jullianow/lcp-exporter/internal/shared"
+)
+
+type ProjectRule struct {
+	Clusters        []string `yaml:"clusters"`
+	EnvTypes        []string `yaml:"envTypes"`
+	OrganizationIDs []string `yaml:"organizationIds"`
+	ProjectID       string   `yaml:"projectId"`
+	Trial           *bool    `yaml:"trial"`
+
+	projectID *regexp.Regexp
+}
+
+// ProjectFilter keeps a project when it matches every include rule field that
+// is set and none of the exclude rules.
+type ProjectFilter struct {
+	Include ProjectRule   `yaml:"include"`
+	Exclude []ProjectRule `yaml:"exclude"`
+}
+
+func LoadProjectFilter(path string) (*ProjectFilter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project filter file: %w", err)
+	}
+
+	var filter ProjectFilter
+	if err := yaml.Unmarshal(data, &filter); err != nil {
+		return nil, fmt.Errorf("failed to parse project filter file: %w", err)
+	}
+
+	if err := filter.Include.compile(); err != nil {
+		return nil, fmt.Errorf("invalid include rule: %w", err)
+	}
+	for i := range filter.Exclude {
+		if err := filter.Exclude[i].compile(); err != nil {
+			return nil, fmt.Errorf("invalid exclude rule %d: %w", i, err)
+		}
+	}
+
+	return &filter, nil
+}
+
+func (f *ProjectFilter) Match(project shared.Projects) bool {
+	if f == nil {
+		return true
+	}
+
+	if !f.Include.matches(project) {
+		return false
+	}
+
+	for _, rule := range f.Exclude {
+		if !rule.empty() && rule.matches(project) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *ProjectRule) compile() error {
+	if r.ProjectID == "" {
+		return nil
+	}
+
+	re, err := regexp.Compile(r.ProjectID)
+	if err != nil {
+		return fmt.Errorf("projectId: %w", err)
+	}
+	r.projectID = re
+	return nil
+}
+
+func (r ProjectRule) empty() bool {
+	return r.projectID == nil && r.Trial == nil && len(r.Clusters) == 0 && len(r.EnvTypes) == 0 && len(r.OrganizationIDs) == 0
+}
+
+func (r ProjectRule) matches(project shared.Projects) bool {
+	if r.projectID != nil && !r.projectID.MatchString(project.ProjectID) {
+		return false
+	}
+	if len(r.Clusters) > 0 && !slices.Contains(r.Clusters, project.Cluster) {
+		return false
+	}
+	if len(r.EnvTypes) > 0 && !slices.ContainsFunc(r.EnvTypes, func(envType string) bool {
+		return strings.EqualFold(envType, project.Metadata.Subscription.EnvType)
+	}) {
+		return false
+	}
+	if len(r.OrganizationIDs) > 0 && !slices.Contains(r.OrganizationIDs, project.OrganizationId) {
+		return false
+	}
+	if r.Trial != nil && *r.Trial != (project.Metadata.Trial == "true") {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal/shared"
+)
+
+func project(projectID, organizationID, cluster, envType, trial string) shared.Projects {
+	p := shared.Projects{ProjectID: projectID, OrganizationId: organizationID, Cluster: cluster}
+	p.Metadata.Subscription.EnvType = envType
+	p.Metadata.Trial = trial
+	return p
+}
+
+func TestLoadProjectFilter(t *testing.T) {
+	path := writeFile(t, `
+include:
+  projectId: "^acme"
+  clusters: [us-1, eu-1]
+exclude:
+  - envTypes: [development]
+  - trial: true
+  - organizationIds: [acme-legacy]
+`)
+
+	filter, err := LoadProjectFilter(path)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		project  shared.Projects
+		expected bool
+	}{
+		{"matches include", project("acme-prd", "acme", "us-1", "PRODUCTION", "false"), true},
+		{"projectId outside include", project("other-prd", "other", "us-1", "PRODUCTION", "false"), false},
+		{"cluster outside include", project("acme-prd", "acme", "ap-1", "PRODUCTION", "false"), false},
+		{"excluded env type", project("acme-dev", "acme", "eu-1", "DEVELOPMENT", "false"), false},
+		{"excluded trial", project("acme-uat", "acme", "eu-1", "UAT", "true"), false},
+		{"excluded organization", project("acme-legacy-prd", "acme-legacy", "eu-1", "PRODUCTION", "false"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, filter.Match(tt.project))
+		})
+	}
+}
+
+func TestProjectFilter_NilMatchesEverything(t *testing.T) {
+	var filter *ProjectFilter
+	assert.True(t, filter.Match(project("proj", "proj", "", "", "")))
+}
+
+func TestProjectFilter_EmptyMatchesEverything(t *testing.T) {
+	filter, err := LoadProjectFilter(writeFile(t, "exclude:\n  - {}\n"))
+	require.NoError(t, err)
+	assert.True(t, filter.Match(project("proj", "proj", "cluster", "PRODUCTION", "true")))
+}
+
+func TestLoadProjectFilter_InvalidRegex(t *testing.T) {
+	_, err := LoadProjectFilter(writeFile(t, "include:\n  projectId: \"[\"\n"))
+	require.Error(t, err)
+
+	_, err = LoadProjectFilter(writeFile(t, "exclude:\n  - projectId: \"(\"\n"))
+	require.Error(t, err)
+}
+
+func TestLoadProjectFilter_MissingFile(t *testing.T) {
+	_, err := LoadProjectFilter("/nonexistent/filter.yaml")
+	require.Error(t, err)
+}
@@ -5,7 +5,8 @@ import (
 )
 
 const (
-	prefix = "lcp_api"
+	prefix         = "lcp_api"
+	exporterPrefix = "lcp_exporter"
 )
 
 func Name(c string) func(string) string {
@@ -13,3 +14,9 @@ func Name(c string) func(string) string {
 		return fmt.Sprintf("%s_%s_%s", prefix, c, s)
 	}
 }
+
+func ExporterName(c string) func(string) string {
+	return func(s string) string {
+		return fmt.Sprintf("%s_%s_%s", exporterPrefix, c, s)
+	}
+}
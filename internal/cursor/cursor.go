@@ -0,0 +1,109 @@
+package cursor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Position marks how far a feed has been read: the newest timestamp seen and
+// the IDs seen at exactly that timestamp, so items sharing it are not re-read.
+type Position struct {
+	At  int64    `json:"at"`
+	IDs []string `json:"ids,omitempty"`
+}
+
+func (p Position) Seen(id string, at int64) bool {
+	return at < p.At || (at == p.At && slices.Contains(p.IDs, id))
+}
+
+func (p Position) Advance(id string, at int64) Position {
+	switch {
+	case at > p.At:
+		return Position{At: at, IDs: []string{id}}
+	case at == p.At && !slices.Contains(p.IDs, id):
+		return Position{At: at, IDs: append(slices.Clone(p.IDs), id)}
+	default:
+		return p
+	}
+}
+
+// Store keeps positions by key and persists them to a JSON file. An empty
+// path keeps them in memory only.
+type Store struct {
+	path      string
+	mu        sync.Mutex
+	positions map[string]Position
+}
+
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, positions: make(map[string]Position)}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cursor file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.positions); err != nil {
+		return nil, fmt.Errorf("failed to parse cursor file: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) Get(key string) (Position, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	position, ok := s.positions[key]
+	return position, ok
+}
+
+func (s *Store) Set(key string, position Position) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.positions[key] = position
+}
+
+// Save writes the positions atomically so a crash never leaves a truncated file.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	data, err := json.Marshal(s.positions)
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write cursor file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write cursor file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cursor file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write cursor file: %w", err)
+	}
+	return nil
+}
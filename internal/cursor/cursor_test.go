@@ -0,0 +1,69 @@
+package cursor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition(t *testing.T) {
+	var position Position
+	assert.False(t, position.Seen("a", 10))
+
+	position = position.Advance("a", 10)
+	position = position.Advance("b", 10)
+	assert.Equal(t, Position{At: 10, IDs: []string{"a", "b"}}, position)
+	assert.True(t, position.Seen("a", 10))
+	assert.True(t, position.Seen("z", 9))
+	assert.False(t, position.Seen("c", 10))
+
+	assert.Equal(t, position, position.Advance("old", 5))
+	assert.Equal(t, Position{At: 11, IDs: []string{"c"}}, position.Advance("c", 11))
+}
+
+func TestStore_PersistsPositions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursor.json")
+
+	store, err := Open(path)
+	require.NoError(t, err)
+
+	_, ok := store.Get("proj")
+	assert.False(t, ok)
+
+	store.Set("proj", Position{At: 42, IDs: []string{"a"}})
+	require.NoError(t, store.Save())
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+
+	position, ok := reopened.Get("proj")
+	assert.True(t, ok)
+	assert.Equal(t, Position{At: 42, IDs: []string{"a"}}, position)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestStore_InMemory(t *testing.T) {
+	store, err := Open("")
+	require.NoError(t, err)
+
+	store.Set("proj", Position{At: 1})
+	require.NoError(t, store.Save())
+
+	position, ok := store.Get("proj")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), position.At)
+}
+
+func TestOpen_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursor.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := Open(path)
+	require.Error(t, err)
+}
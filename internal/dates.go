@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jullianow/lcp-exporter/internal/shared"
+)
+
+type DateWindowMode string
+
+const (
+	DateWindowLast  DateWindowMode = "last"
+	DateWindowDay   DateWindowMode = "day"
+	DateWindowMonth DateWindowMode = "month"
+)
+
+func ParseDateWindowMode(s string) (DateWindowMode, error) {
+	switch mode := DateWindowMode(s); mode {
+	case DateWindowLast, DateWindowDay, DateWindowMonth:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown date window mode %q (expected last, day or month)", s)
+	}
+}
+
+type DateWindow struct {
+	Mode     DateWindowMode
+	Duration time.Duration
+	Clock    func() time.Time
+}
+
+func (w DateWindow) Now() time.Time {
+	if w.Clock != nil {
+		return w.Clock().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (w DateWindow) Range() shared.DateRange {
+	now := w.Now()
+
+	switch w.Mode {
+	case DateWindowDay:
+		return DayToDate(now)
+	case DateWindowMonth:
+		return MonthToDate(now)
+	default:
+		return CalculateDatesAt(now, w.Duration)
+	}
+}
+
+func CalculateDatesAt(now time.Time, duration time.Duration) shared.DateRange {
+	start := now.Add(-duration).Truncate(24 * time.Hour)
+	return dateRange(start, now)
+}
+
+func DayToDate(now time.Time) shared.DateRange {
+	return dateRange(now.Truncate(24*time.Hour), now)
+}
+
+func MonthToDate(now time.Time) shared.DateRange {
+	return dateRange(startOfMonth(now), now)
+}
+
+func PreviousMonth(now time.Time) shared.DateRange {
+	end := startOfMonth(now).Add(-time.Second)
+	return dateRange(startOfMonth(end), end)
+}
+
+// IntervalRange returns the last complete interval before now, aligned to
+// interval boundaries so that consecutive polls cover adjacent ranges.
+func IntervalRange(now time.Time, interval time.Duration) shared.DateRange {
+	end := now.UTC().Truncate(interval)
+	return shared.DateRange{
+		From: end.Add(-interval).Format(time.RFC3339),
+		End:  end.Format(time.RFC3339),
+	}
+}
+
+func MonthProgress(now time.Time) float64 {
+	start := startOfMonth(now)
+	end := start.AddDate(0, 1, 0)
+	return now.Sub(start).Seconds() / end.Sub(start).Seconds()
+}
+
+func DateRangeStartMillis(dataRange shared.DateRange) int64 {
+	start, err := time.Parse(time.RFC3339, dataRange.From)
+	if err != nil {
+		return 0
+	}
+	return start.UnixMilli()
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func endOfDay(t time.Time) time.Time {
+	return t.Truncate(24 * time.Hour).Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+}
+
+func dateRange(start, now time.Time) shared.DateRange {
+	return shared.DateRange{
+		From: start.Format(time.RFC3339),
+		End:  endOfDay(now).Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal/shared"
+)
+
+func TestParseDateWindowMode(t *testing.T) {
+	for _, mode := range []string{"last", "day", "month"} {
+		parsed, err := ParseDateWindowMode(mode)
+		require.NoError(t, err)
+		assert.Equal(t, DateWindowMode(mode), parsed)
+	}
+
+	_, err := ParseDateWindowMode("week")
+	assert.Error(t, err)
+}
+
+func TestDateWindowRange(t *testing.T) {
+	now := time.Date(2025, 3, 17, 14, 30, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	tests := []struct {
+		name     string
+		window   DateWindow
+		expected shared.DateRange
+	}{
+		{
+			name:     "last without duration",
+			window:   DateWindow{Mode: DateWindowLast, Clock: clock},
+			expected: shared.DateRange{From: "2025-03-17T00:00:00Z", End: "2025-03-17T23:59:59Z"},
+		},
+		{
+			name:     "last 48h",
+			window:   DateWindow{Mode: DateWindowLast, Duration: 48 * time.Hour, Clock: clock},
+			expected: shared.DateRange{From: "2025-03-15T00:00:00Z", End: "2025-03-17T23:59:59Z"},
+		},
+		{
+			name:     "calendar day to date",
+			window:   DateWindow{Mode: DateWindowDay, Duration: 48 * time.Hour, Clock: clock},
+			expected: shared.DateRange{From: "2025-03-17T00:00:00Z", End: "2025-03-17T23:59:59Z"},
+		},
+		{
+			name:     "billing month to date",
+			window:   DateWindow{Mode: DateWindowMonth, Clock: clock},
+			expected: shared.DateRange{From: "2025-03-01T00:00:00Z", End: "2025-03-17T23:59:59Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.window.Range())
+		})
+	}
+}
+
+func TestDateWindowRange_FollowsClock(t *testing.T) {
+	now := time.Date(2025, 1, 31, 23, 0, 0, 0, time.UTC)
+	window := DateWindow{Mode: DateWindowMonth, Clock: func() time.Time { return now }}
+
+	assert.Equal(t, shared.DateRange{From: "2025-01-01T00:00:00Z", End: "2025-01-31T23:59:59Z"}, window.Range())
+
+	now = now.Add(2 * time.Hour)
+	assert.Equal(t, shared.DateRange{From: "2025-02-01T00:00:00Z", End: "2025-02-01T23:59:59Z"}, window.Range())
+}
+
+func TestDateWindowRange_ConvertsToUTC(t *testing.T) {
+	location := time.FixedZone("UTC-3", -3*60*60)
+	now := time.Date(2025, 3, 31, 22, 0, 0, 0, location)
+	window := DateWindow{Mode: DateWindowMonth, Clock: func() time.Time { return now }}
+
+	assert.Equal(t, shared.DateRange{From: "2025-04-01T00:00:00Z", End: "2025-04-01T23:59:59Z"}, window.Range())
+}
+
+func TestPreviousMonth(t *testing.T) {
+	tests := []struct {
+		now      time.Time
+		expected shared.DateRange
+	}{
+		{time.Date(2025, 3, 17, 14, 30, 0, 0, time.UTC), shared.DateRange{From: "2025-02-01T00:00:00Z", End: "2025-02-28T23:59:59Z"}},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), shared.DateRange{From: "2024-12-01T00:00:00Z", End: "2024-12-31T23:59:59Z"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.now.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, PreviousMonth(tt.now))
+		})
+	}
+}
+
+func TestMonthProgress(t *testing.T) {
+	assert.Equal(t, 0.0, MonthProgress(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0.5, MonthProgress(time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC)))
+	assert.InDelta(t, 1.0, MonthProgress(time.Date(2025, 4, 30, 23, 59, 59, 0, time.UTC)), 0.001)
+}
+
+func TestIntervalRange(t *testing.T) {
+	now := time.Date(2025, 4, 16, 10, 7, 30, 0, time.UTC)
+
+	assert.Equal(t, shared.DateRange{From: "2025-04-16T10:06:00Z", End: "2025-04-16T10:07:00Z"}, IntervalRange(now, time.Minute))
+	assert.Equal(t, shared.DateRange{From: "2025-04-16T10:00:00Z", End: "2025-04-16T10:05:00Z"}, IntervalRange(now, 5*time.Minute))
+}
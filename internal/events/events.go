@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/cursor"
+)
+
+type Event struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	ProjectID string    `json:"projectId"`
+	ServiceID string    `json:"serviceId"`
+	Timestamp time.Time `json:"timestamp"`
+	Payload   any       `json:"payload"`
+}
+
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, event Event) error
+}
+
+type trackedSink struct {
+	sink     Sink
+	seen     map[string]time.Time
+	position cursor.Position
+	restored cursor.Position
+}
+
+type Dispatcher struct {
+	mu     sync.Mutex
+	store  *cursor.Store
+	sinks  []*trackedSink
+	latest map[string]struct{}
+}
+
+// NewDispatcher sends every event once per sink. The newest event delivered to
+// each sink is kept in store, so that after a restart the events up to it are
+// not sent again. A nil store keeps it in memory only.
+func NewDispatcher(store *cursor.Store, sinks ...Sink) *Dispatcher {
+	d := &Dispatcher{store: store}
+	for _, sink := range sinks {
+		tracked := &trackedSink{sink: sink, seen: make(map[string]time.Time)}
+		if store != nil {
+			tracked.restored, _ = store.Get(cursorKey(sink))
+			tracked.position = tracked.restored
+		}
+		d.sinks = append(d.sinks, tracked)
+	}
+	return d
+}
+
+func cursorKey(sink Sink) string {
+	return "events/" + sink.Name()
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, events []Event) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.latest = make(map[string]struct{}, len(events))
+	for _, event := range events {
+		d.latest[event.ID] = struct{}{}
+	}
+
+	// Sending in timestamp order keeps the persisted position from skipping an
+	// older event that failed to send.
+	events = slices.Clone(events)
+	slices.SortStableFunc(events, func(a, b Event) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	for _, tracked := range d.sinks {
+		for _, event := range events {
+			if _, seen := tracked.seen[event.ID]; seen {
+				continue
+			}
+
+			at := event.Timestamp.UnixMilli()
+			if tracked.restored.Seen(event.ID, at) {
+				tracked.seen[event.ID] = event.Timestamp
+				continue
+			}
+
+			if err := tracked.sink.Send(ctx, event); err != nil {
+				internal.LogError("EventDispatcher", "Failed to send event %s to %s, retrying on next poll: %v", event.ID, tracked.sink.Name(), err)
+				break
+			}
+
+			tracked.seen[event.ID] = event.Timestamp
+			tracked.position = tracked.position.Advance(event.ID, at)
+		}
+	}
+
+	d.save()
+}
+
+func (d *Dispatcher) save() {
+	if d.store == nil {
+		return
+	}
+
+	for _, tracked := range d.sinks {
+		d.store.Set(cursorKey(tracked.sink), tracked.position)
+	}
+	if err := d.store.Save(); err != nil {
+		internal.LogError("EventDispatcher", "Failed to persist event cursor: %v", err)
+	}
+}
+
+func (d *Dispatcher) Prune(before time.Time) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, tracked := range d.sinks {
+		for id, timestamp := range tracked.seen {
+			if _, ok := d.latest[id]; ok {
+				continue
+			}
+			if timestamp.Before(before) {
+				delete(tracked.seen, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jullianow/lcp-exporter/internal/cursor"
+)
+
+type recordingSink struct {
+	mu   sync.Mutex
+	sent []string
+	fail bool
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Send(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return fmt.Errorf("sink unavailable")
+	}
+	s.sent = append(s.sent, event.ID)
+	return nil
+}
+
+func newEvent(id string, timestamp time.Time) Event {
+	return Event{ID: id, Kind: "test", ProjectID: "proj-1", ServiceID: "liferay", Timestamp: timestamp}
+}
+
+func TestDispatcher_PublishesEachEventOnce(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	sink := &recordingSink{}
+	dispatcher := NewDispatcher(nil, sink)
+
+	dispatcher.Publish(context.Background(), []Event{newEvent("a", now), newEvent("b", now)})
+	dispatcher.Publish(context.Background(), []Event{newEvent("a", now), newEvent("b", now), newEvent("c", now)})
+
+	assert.Equal(t, []string{"a", "b", "c"}, sink.sent)
+}
+
+func TestDispatcher_RetriesFailedEventsOnNextPublish(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	healthy := &recordingSink{}
+	failing := &recordingSink{fail: true}
+	dispatcher := NewDispatcher(nil, healthy, failing)
+
+	dispatcher.Publish(context.Background(), []Event{newEvent("a", now)})
+	assert.Equal(t, []string{"a"}, healthy.sent)
+	assert.Empty(t, failing.sent)
+
+	failing.fail = false
+	dispatcher.Publish(context.Background(), []Event{newEvent("a", now)})
+	assert.Equal(t, []string{"a"}, healthy.sent)
+	assert.Equal(t, []string{"a"}, failing.sent)
+}
+
+func TestDispatcher_Prune(t *testing.T) {
+	old := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	sink := &recordingSink{}
+	dispatcher := NewDispatcher(nil, sink)
+
+	dispatcher.Publish(context.Background(), []Event{newEvent("old", old), newEvent("still-reported", old)})
+	dispatcher.Publish(context.Background(), []Event{newEvent("still-reported", old)})
+	dispatcher.Prune(cutoff)
+
+	assert.NotContains(t, dispatcher.sinks[0].seen, "old")
+	assert.Contains(t, dispatcher.sinks[0].seen, "still-reported")
+
+	dispatcher.Publish(context.Background(), []Event{newEvent("still-reported", old)})
+	assert.Equal(t, []string{"old", "still-reported"}, sink.sent)
+}
+
+func TestDispatcher_DoesNotResendAfterRestart(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "events.json")
+
+	store, err := cursor.Open(path)
+	require.NoError(t, err)
+	sink := &recordingSink{}
+	NewDispatcher(store, sink).Publish(context.Background(), []Event{newEvent("b", now.Add(time.Minute)), newEvent("a", now)})
+	assert.Equal(t, []string{"a", "b"}, sink.sent)
+
+	store, err = cursor.Open(path)
+	require.NoError(t, err)
+	restarted := &recordingSink{}
+	NewDispatcher(store, restarted).Publish(context.Background(), []Event{newEvent("a", now), newEvent("b", now.Add(time.Minute)), newEvent("c", now.Add(time.Hour))})
+	assert.Equal(t, []string{"c"}, restarted.sent)
+}
+
+func TestDispatcher_Nil(t *testing.T) {
+	var dispatcher *Dispatcher
+	dispatcher.Publish(context.Background(), []Event{newEvent("a", time.Now())})
+	dispatcher.Prune(time.Now())
+}
+
+func TestLogSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewLogSink(&buf)
+
+	event := newEvent("a", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	event.Payload = map[string]string{"enabledByEmail": "user@liferay.com"}
+	require.NoError(t, sink.Send(context.Background(), event))
+	require.NoError(t, sink.Send(context.Background(), newEvent("b", time.Now())))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+	assert.Equal(t, "a", decoded["id"])
+	assert.Equal(t, "2025-03-01T00:00:00Z", decoded["timestamp"])
+	assert.Equal(t, "user@liferay.com", decoded["payload"].(map[string]any)["enabledByEmail"])
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var event Event
+		require.NoError(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL)
+	require.NoError(t, sink.Send(context.Background(), newEvent("a", time.Now())))
+	require.Len(t, received, 1)
+	assert.Equal(t, "a", received[0].ID)
+}
+
+func TestWebhookSink_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL)
+	assert.Error(t, sink.Send(context.Background(), newEvent("a", time.Now())))
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/jullianow/lcp-exporter/internal"
+)
+
+const scrapeTimeoutHeader = "X-Prometheus-Scrape-Timeout-Seconds"
+
+func Handler(gatherer prometheus.Gatherer, jobs []*Job, timeoutOffset time.Duration, opts promhttp.HandlerOpts) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := ScrapeContext(r, timeoutOffset)
+		defer cancel()
+
+		registry := prometheus.NewRegistry()
+		for _, job := range jobs {
+			if err := registry.Register(job.Bind(ctx)); err != nil {
+				internal.LogError("Handler", "Failed to register collector %s: %v", job.Name(), err)
+			}
+		}
+
+		promhttp.HandlerFor(prometheus.Gatherers{gatherer, registry}, opts).ServeHTTP(w, r)
+	})
+}
+
+func ScrapeContext(r *http.Request, timeoutOffset time.Duration) (context.Context, context.CancelFunc) {
+	value := r.Header.Get(scrapeTimeoutHeader)
+	if value == "" {
+		return context.WithCancel(r.Context())
+	}
+
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds <= 0 {
+		internal.LogWarn("Handler", "Invalid %s header: %q", scrapeTimeoutHeader, value)
+		return context.WithCancel(r.Context())
+	}
+
+	timeout := time.Duration(seconds * float64(time.Second))
+	if timeout > timeoutOffset {
+		timeout -= timeoutOffset
+	}
+
+	return context.WithTimeout(r.Context(), timeout)
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+type blockingSource struct {
+	desc *prometheus.Desc
+	err  chan error
+}
+
+func (b *blockingSource) Describe(ch chan<- *prometheus.Desc) {
+	ch <- b.desc
+}
+
+func (b *blockingSource) Update(ctx context.Context, _ chan<- prometheus.Metric) error {
+	<-ctx.Done()
+	b.err <- ctx.Err()
+	return ctx.Err()
+}
+
+func TestScrapeContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		offset      time.Duration
+		hasDeadline bool
+		expected    time.Duration
+	}{
+		{"no header", "", 500 * time.Millisecond, false, 0},
+		{"invalid header", "abc", 500 * time.Millisecond, false, 0},
+		{"with offset", "10", 500 * time.Millisecond, true, 9500 * time.Millisecond},
+		{"offset larger than timeout", "0.2", 500 * time.Millisecond, true, 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/metrics", nil)
+			if tt.header != "" {
+				r.Header.Set(scrapeTimeoutHeader, tt.header)
+			}
+
+			start := time.Now()
+			ctx, cancel := ScrapeContext(r, tt.offset)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			require.Equal(t, tt.hasDeadline, ok)
+			if ok {
+				require.InDelta(t, tt.expected.Seconds(), deadline.Sub(start).Seconds(), 0.05)
+			}
+		})
+	}
+}
+
+func TestHandler_CancelsSynchronousJobOnScrapeTimeout(t *testing.T) {
+	source := &blockingSource{
+		desc: prometheus.NewDesc("lcp_api_blocking_value", "Blocking value", nil, nil),
+		err:  make(chan error, 1),
+	}
+	job := NewJob("blocking", source, 0)
+
+	handler := Handler(prometheus.NewRegistry(), []*Job{job}, 0, promhttp.HandlerOpts{})
+
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	r.Header.Set(scrapeTimeoutHeader, "0.05")
+	recorder := httptest.NewRecorder()
+
+	start := time.Now()
+	handler.ServeHTTP(recorder, r)
+	require.Less(t, time.Since(start), time.Second)
+
+	require.ErrorIs(t, <-source.err, context.DeadlineExceeded)
+	require.Contains(t, recorder.Body.String(), `lcp_exporter_collector_success{collector="blocking"} 0`)
+}
+
+func TestHandler_ServesSnapshotAndGatherer(t *testing.T) {
+	source := newFakeSource()
+	job := NewJob("fake", source, time.Hour)
+	require.NoError(t, job.Refresh(context.Background()))
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "lcp_exporter_test_total", Help: "Test counter"})
+	registry.MustRegister(counter)
+
+	handler := Handler(registry, []*Job{job}, 0, promhttp.HandlerOpts{})
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	output := recorder.Body.String()
+	require.Contains(t, output, `lcp_api_fake_value 1`)
+	require.Contains(t, output, `lcp_exporter_test_total 0`)
+	require.Equal(t, int64(1), source.calls.Load())
+}
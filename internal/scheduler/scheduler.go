@@ -0,0 +1,260 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/jullianow/lcp-exporter/internal"
+)
+
+type Source interface {
+	Describe(ch chan<- *prometheus.Desc)
+	Update(ctx context.Context, ch chan<- prometheus.Metric) error
+}
+
+// PartialError is returned by sources that collected only some of their
+// targets. The snapshot is still replaced, but the refresh counts as failed.
+type PartialError struct {
+	Failed int
+	Total  int
+	Err    error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%d of %d targets failed: %v", e.Failed, e.Total, e.Err)
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+type Job struct {
+	name     string
+	source   Source
+	interval time.Duration
+	now      func() time.Time
+
+	// refreshMu serializes calls to the source, whose Update is not safe to
+	// run concurrently when it keeps state between polls.
+	refreshMu sync.Mutex
+
+	mu           sync.RWMutex
+	metrics      []prometheus.Metric
+	updatedAt    time.Time
+	attemptedAt  time.Time
+	lastSuccess  bool
+	lastDuration time.Duration
+	lastFailed   int
+
+	duration      *prometheus.Desc
+	failedTargets *prometheus.Desc
+	snapshotAge   *prometheus.Desc
+	success       *prometheus.Desc
+}
+
+func NewJob(name string, source Source, interval time.Duration) *Job {
+	fqName := internal.ExporterName("collector")
+	constLabels := prometheus.Labels{"collector": name}
+
+	return &Job{
+		name:     name,
+		source:   source,
+		interval: interval,
+		now:      time.Now,
+		duration: prometheus.NewDesc(
+			fqName("duration_seconds"),
+			"Duration of the last refresh of the collector in seconds",
+			nil,
+			constLabels,
+		),
+		failedTargets: prometheus.NewDesc(
+			fqName("failed_targets"),
+			"Number of projects or organizations that failed in the last refresh of the collector",
+			nil,
+			constLabels,
+		),
+		snapshotAge: prometheus.NewDesc(
+			fqName("snapshot_age_seconds"),
+			"Seconds since the last successful refresh of the collector snapshot",
+			nil,
+			constLabels,
+		),
+		success: prometheus.NewDesc(
+			fqName("success"),
+			"1 if the last refresh of the collector succeeded, 0 otherwise",
+			nil,
+			constLabels,
+		),
+	}
+}
+
+func (j *Job) Name() string {
+	return j.name
+}
+
+func (j *Job) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *Job) Describe(ch chan<- *prometheus.Desc) {
+	j.source.Describe(ch)
+	ch <- j.duration
+	ch <- j.failedTargets
+	ch <- j.snapshotAge
+	ch <- j.success
+}
+
+func (j *Job) Collect(ch chan<- prometheus.Metric) {
+	j.collect(context.Background(), ch)
+}
+
+func (j *Job) Bind(ctx context.Context) prometheus.Collector {
+	return &boundJob{job: j, ctx: ctx}
+}
+
+func (j *Job) collect(ctx context.Context, ch chan<- prometheus.Metric) {
+	if j.interval <= 0 {
+		_ = j.Refresh(ctx)
+	}
+
+	j.mu.RLock()
+	metrics := j.metrics
+	updatedAt := j.updatedAt
+	attemptedAt := j.attemptedAt
+	lastSuccess := j.lastSuccess
+	lastDuration := j.lastDuration
+	lastFailed := j.lastFailed
+	j.mu.RUnlock()
+
+	if attemptedAt.IsZero() {
+		return
+	}
+
+	var success float64
+	if lastSuccess {
+		success = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		j.success,
+		prometheus.GaugeValue,
+		success,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		j.duration,
+		prometheus.GaugeValue,
+		lastDuration.Seconds(),
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		j.failedTargets,
+		prometheus.GaugeValue,
+		float64(lastFailed),
+	)
+
+	if updatedAt.IsZero() {
+		return
+	}
+
+	for _, metric := range metrics {
+		ch <- metric
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		j.snapshotAge,
+		prometheus.GaugeValue,
+		j.now().Sub(updatedAt).Seconds(),
+	)
+}
+
+func (j *Job) Refresh(ctx context.Context) error {
+	j.refreshMu.Lock()
+	defer j.refreshMu.Unlock()
+
+	ch := make(chan prometheus.Metric)
+	done := make(chan struct{})
+
+	var metrics []prometheus.Metric
+	go func() {
+		defer close(done)
+		for metric := range ch {
+			metrics = append(metrics, metric)
+		}
+	}()
+
+	start := j.now()
+	err := j.source.Update(ctx, ch)
+	close(ch)
+	<-done
+	end := j.now()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var partial *PartialError
+	isPartial := errors.As(err, &partial)
+
+	j.attemptedAt = end
+	j.lastDuration = end.Sub(start)
+	j.lastSuccess = err == nil
+	j.lastFailed = 0
+	if isPartial {
+		j.lastFailed = partial.Failed
+	}
+
+	if err != nil && !isPartial {
+		internal.LogWarn("Scheduler", "Refresh of %s failed, keeping previous snapshot: %v", j.name, err)
+		return err
+	}
+
+	j.metrics = metrics
+	j.updatedAt = end
+
+	if isPartial {
+		internal.LogWarn("Scheduler", "Refresh of %s partially failed: %d of %d targets", j.name, partial.Failed, partial.Total)
+		return err
+	}
+
+	internal.LogDebug("Scheduler", "Refreshed %s with %d metrics", j.name, len(metrics))
+	return nil
+}
+
+func (j *Job) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		refreshCtx, cancel := context.WithTimeout(ctx, j.interval)
+		_ = j.Refresh(refreshCtx)
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type boundJob struct {
+	job *Job
+	ctx context.Context
+}
+
+func (b *boundJob) Describe(ch chan<- *prometheus.Desc) {
+	b.job.Describe(ch)
+}
+
+func (b *boundJob) Collect(ch chan<- prometheus.Metric) {
+	b.job.collect(b.ctx, ch)
+}
@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	calls atomic.Int64
+	fail  atomic.Bool
+	desc  *prometheus.Desc
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		desc: prometheus.NewDesc("lcp_api_fake_value", "Fake value", nil, nil),
+	}
+}
+
+func (f *fakeSource) Describe(ch chan<- *prometheus.Desc) {
+	ch <- f.desc
+}
+
+func (f *fakeSource) Update(_ context.Context, ch chan<- prometheus.Metric) error {
+	calls := f.calls.Add(1)
+	if f.fail.Load() {
+		return fmt.Errorf("fake failure")
+	}
+	ch <- prometheus.MustNewConstMetric(f.desc, prometheus.GaugeValue, float64(calls))
+	return nil
+}
+
+func scrape(t *testing.T, c prometheus.Collector) string {
+	reg := prometheus.NewRegistry()
+	require.NoError(t, reg.Register(c))
+
+	recorder := httptest.NewRecorder()
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	return recorder.Body.String()
+}
+
+func TestJob_ServesSnapshotWithoutCallingSource(t *testing.T) {
+	source := newFakeSource()
+	job := NewJob("fake", source, time.Hour)
+
+	require.NoError(t, job.Refresh(context.Background()))
+
+	scrape(t, job)
+	output := scrape(t, job)
+
+	require.Equal(t, int64(1), source.calls.Load())
+	require.Contains(t, output, `lcp_api_fake_value 1`)
+	require.Contains(t, output, `lcp_exporter_collector_snapshot_age_seconds{collector="fake"}`)
+}
+
+func TestJob_KeepsLastGoodSnapshotOnFailure(t *testing.T) {
+	source := newFakeSource()
+	job := NewJob("fake", source, time.Hour)
+
+	start := time.Unix(1700000000, 0)
+	job.now = func() time.Time { return start }
+	require.NoError(t, job.Refresh(context.Background()))
+
+	source.fail.Store(true)
+	job.now = func() time.Time { return start.Add(90 * time.Second) }
+	require.Error(t, job.Refresh(context.Background()))
+
+	output := scrape(t, job)
+	require.Contains(t, output, `lcp_api_fake_value 1`)
+	require.Contains(t, output, `lcp_exporter_collector_snapshot_age_seconds{collector="fake"} 90`)
+	require.Contains(t, output, `lcp_exporter_collector_success{collector="fake"} 0`)
+}
+
+func TestJob_NoSnapshotYet(t *testing.T) {
+	source := newFakeSource()
+	source.fail.Store(true)
+	job := NewJob("fake", source, time.Hour)
+
+	require.Error(t, job.Refresh(context.Background()))
+
+	output := scrape(t, job)
+	require.NotContains(t, output, "lcp_api_fake_value")
+	require.NotContains(t, output, "lcp_exporter_collector_snapshot_age_seconds")
+	require.Contains(t, output, `lcp_exporter_collector_success{collector="fake"} 0`)
+	require.Contains(t, output, `lcp_exporter_collector_duration_seconds{collector="fake"}`)
+}
+
+func TestJob_ZeroIntervalCollectsOnScrape(t *testing.T) {
+	source := newFakeSource()
+	job := NewJob("fake", source, 0)
+
+	scrape(t, job)
+	output := scrape(t, job)
+
+	require.Equal(t, int64(2), source.calls.Load())
+	require.Contains(t, output, `lcp_api_fake_value 2`)
+	require.Contains(t, output, `lcp_exporter_collector_success{collector="fake"} 1`)
+}
+
+func TestJob_DurationOfLastRefresh(t *testing.T) {
+	source := newFakeSource()
+	job := NewJob("fake", source, time.Hour)
+
+	start := time.Unix(1700000000, 0)
+	calls := 0
+	job.now = func() time.Time {
+		calls++
+		return start.Add(time.Duration(calls) * 1500 * time.Millisecond)
+	}
+	require.NoError(t, job.Refresh(context.Background()))
+
+	output := scrape(t, job)
+	require.Contains(t, output, `lcp_exporter_collector_duration_seconds{collector="fake"} 1.5`)
+	require.Contains(t, output, `lcp_exporter_collector_success{collector="fake"} 1`)
+}
+
+func TestJob_RunRefreshesOnInterval(t *testing.T) {
+	source := newFakeSource()
+	job := NewJob("fake", source, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go job.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		return source.calls.Load() >= 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestJob_RunRefreshesImmediately(t *testing.T) {
+	source := newFakeSource()
+	job := NewJob("fake", source, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go job.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		return source.calls.Load() == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+type slowSource struct {
+	*fakeSource
+	running atomic.Int64
+	overlap atomic.Bool
+}
+
+func (s *slowSource) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	if s.running.Add(1) > 1 {
+		s.overlap.Store(true)
+	}
+	defer s.running.Add(-1)
+
+	time.Sleep(10 * time.Millisecond)
+	return s.fakeSource.Update(ctx, ch)
+}
+
+func TestJob_SerializesConcurrentRefreshes(t *testing.T) {
+	source := &slowSource{fakeSource: newFakeSource()}
+	job := NewJob("fake", source, 0)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scrape(t, job)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int64(4), source.calls.Load())
+	require.False(t, source.overlap.Load())
+}
+
+type partialSource struct {
+	*fakeSource
+}
+
+func (p partialSource) Update(ctx context.Context, ch chan<- prometheus.Metric) error {
+	if err := p.fakeSource.Update(ctx, ch); err != nil {
+		return err
+	}
+	return &PartialError{Failed: 2, Total: 5, Err: fmt.Errorf("fake failure")}
+}
+
+func TestJob_PartialFailureReplacesSnapshotButFails(t *testing.T) {
+	job := NewJob("fake", partialSource{newFakeSource()}, time.Hour)
+
+	require.Error(t, job.Refresh(context.Background()))
+
+	output := scrape(t, job)
+	require.Contains(t, output, `lcp_api_fake_value 1`)
+	require.Contains(t, output, `lcp_exporter_collector_success{collector="fake"} 0`)
+	require.Contains(t, output, `lcp_exporter_collector_failed_targets{collector="fake"} 2`)
+}
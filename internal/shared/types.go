@@ -73,18 +73,30 @@ type AutoscaleCost struct {
 	Currency string  `json:"currency"`
 }
 
+type AutoscaleScaleLimitsChange struct {
+	ChangedAt      int64  `json:"changedAt"`
+	ChangedByEmail string `json:"changedByEmail"`
+	MaxInstances   int    `json:"maxInstances"`
+	MinInstances   int    `json:"minInstances"`
+}
+
 type AutoscaleActivationHistory struct {
-	DisabledAt      int64  `json:"disabledAt"`
-	DisabledByEmail string `json:"disabledByEmail"`
-	EnabledAt       int64  `json:"enabledAt"`
-	EnabledByEmail  string `json:"enabledByEmail"`
-	ProjectID       string `json:"projectId"`
-	ServiceID       string `json:"serviceId"`
-	MaxInstances    int    `json:"maxInstances"`
+	Availability       string                       `json:"availability"`
+	DisabledAt         int64                        `json:"disabledAt"`
+	DisabledByEmail    string                       `json:"disabledByEmail"`
+	EnabledAt          int64                        `json:"enabledAt"`
+	EnabledByEmail     string                       `json:"enabledByEmail"`
+	MaxInstances       int                          `json:"maxInstances"`
+	MinInstances       int                          `json:"minInstances"`
+	ProjectID          string                       `json:"projectId"`
+	ScaleLimitsChanges []AutoscaleScaleLimitsChange `json:"scaleLimitsChanges"`
+	ServiceID          string                       `json:"serviceId"`
 }
 
 type AutoscaleScalingHistory struct {
+	ActiveTimeMs            int64  `json:"activeTimeMs"`
 	ActiveTimePerInstanceMs int64  `json:"activeTimePerInstanceMs"`
+	Availability            string `json:"availability"`
 	EndedAt                 int64  `json:"endedAt"`
 	NumAdditionalInstances  int    `json:"numAdditionalInstances"`
 	ProjectID               string `json:"projectId"`
@@ -106,3 +118,121 @@ type Autoscale struct {
 	ScalingHistory          []AutoscaleScalingHistory    `json:"scaleHistory"`
 	SubtotalsByProjectId    map[string]AutoscaleProject  `json:"subtotalsByProjectId"`
 }
+
+type ServiceResources struct {
+	CPU    float64 `json:"cpu"`
+	Memory int64   `json:"memory"`
+}
+
+type Service struct {
+	Health    string           `json:"health"`
+	Image     string           `json:"image"`
+	Instances int              `json:"instances"`
+	Limits    ServiceResources `json:"limits"`
+	ProjectID string           `json:"projectId"`
+	Requests  ServiceResources `json:"requests"`
+	Scale     int              `json:"scale"`
+	ServiceID string           `json:"serviceId"`
+	Status    string           `json:"status"`
+}
+
+type Build struct {
+	CreatedAt  int64  `json:"createdAt"`
+	FinishedAt int64  `json:"finishedAt"`
+	ID         string `json:"id"`
+	ProjectID  string `json:"projectId"`
+	StartedAt  int64  `json:"startedAt"`
+	Status     string `json:"status"`
+}
+
+type Deployment struct {
+	BuildID    string `json:"buildId"`
+	CreatedAt  int64  `json:"createdAt"`
+	FinishedAt int64  `json:"finishedAt"`
+	ID         string `json:"id"`
+	ProjectID  string `json:"projectId"`
+	Status     string `json:"status"`
+}
+
+type Backup struct {
+	CreatedAt  int64  `json:"createdAt"`
+	FinishedAt int64  `json:"finishedAt"`
+	ID         string `json:"id"`
+	ProjectID  string `json:"projectId"`
+	Size       int64  `json:"size"`
+	Status     string `json:"status"`
+}
+
+type BackupSettings struct {
+	Automated     bool   `json:"automated"`
+	RetentionDays int    `json:"retentionDays"`
+	Schedule      string `json:"schedule"`
+}
+
+type CustomDomain struct {
+	Certificate        string `json:"certificate"`
+	Domain             string `json:"domain"`
+	ProjectID          string `json:"projectId"`
+	ServiceID          string `json:"serviceId"`
+	VerificationStatus string `json:"verificationStatus"`
+}
+
+type ServiceUsage struct {
+	CPU                  float64 `json:"cpu"`
+	Memory               int64   `json:"memory"`
+	NetworkReceiveBytes  int64   `json:"networkReceiveBytes"`
+	NetworkTransmitBytes int64   `json:"networkTransmitBytes"`
+}
+
+type TeamMember struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type TeamInvitation struct {
+	CreatedAt int64  `json:"createdAt"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+}
+
+type Activity struct {
+	CreatedAt   int64  `json:"createdAt"`
+	Description string `json:"description"`
+	ID          string `json:"id"`
+	ProjectID   string `json:"projectId"`
+	ServiceID   string `json:"serviceId"`
+	Type        string `json:"type"`
+}
+
+// ConfigEntry describes an environment variable or secret. Values are
+// deliberately not decoded so they can never end up in logs or metrics.
+type ConfigEntry struct {
+	Name      string `json:"name"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+type Alert struct {
+	CreatedAt  int64  `json:"createdAt"`
+	ID         string `json:"id"`
+	ProjectID  string `json:"projectId"`
+	ResolvedAt int64  `json:"resolvedAt"`
+	ServiceID  string `json:"serviceId"`
+	Severity   string `json:"severity"`
+	Status     string `json:"status"`
+	Type       string `json:"type"`
+}
+
+type SubscriptionQuota struct {
+	Limit float64 `json:"limit"`
+	Used  float64 `json:"used"`
+}
+
+type Subscription struct {
+	Plan   string `json:"plan"`
+	Quotas struct {
+		CPU          SubscriptionQuota `json:"cpu"`
+		Environments SubscriptionQuota `json:"environments"`
+		Memory       SubscriptionQuota `json:"memory"`
+		Storage      SubscriptionQuota `json:"storage"`
+	} `json:"quotas"`
+}
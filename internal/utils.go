@@ -1,10 +1,13 @@
 package internal
 
 import (
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/pem"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +21,13 @@ func BoolToString(b bool) string {
 	return strconv.FormatBool(b)
 }
 
+func BoolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func IntToString(value interface{}) string {
 	switch v := value.(type) {
 	case int:
@@ -50,20 +60,87 @@ func LogFatal(context, format string, args ...interface{}) {
 }
 
 func CalculateDates(duration time.Duration) shared.DateRange {
-	now := time.Now().UTC()
-	start := now.Add(-duration).Truncate(24 * time.Hour)
-	end := now.Truncate(24 * time.Hour).Add(23*time.Hour + 59*time.Minute + 59*time.Second)
-
-	return shared.DateRange{
-		From: start.Format(time.RFC3339),
-		End:  end.Format(time.RFC3339),
-	}
+	return CalculateDatesAt(time.Now().UTC(), duration)
 }
 
 func JoinStrings(list []string, separator string) string {
 	return strings.Join(list, separator)
 }
 
+func ChunkStrings(list []string, size int) [][]string {
+	if len(list) == 0 {
+		return nil
+	}
+	if size <= 0 || size >= len(list) {
+		return [][]string{list}
+	}
+
+	var chunks [][]string
+	for start := 0; start < len(list); start += size {
+		end := min(start+size, len(list))
+		chunks = append(chunks, list[start:end])
+	}
+	return chunks
+}
+
+func MergeAutoscale(stats []shared.Autoscale) shared.Autoscale {
+	merged := shared.Autoscale{
+		SubtotalsByProjectId: make(map[string]shared.AutoscaleProject),
+	}
+	seenChildProjectIds := make(map[string]struct{})
+
+	for _, stat := range stats {
+		merged.ActivationHistory = append(merged.ActivationHistory, stat.ActivationHistory...)
+		merged.ScalingHistory = append(merged.ScalingHistory, stat.ScalingHistory...)
+
+		for _, childProjectId := range stat.IncludedChildProjectIds {
+			if _, seen := seenChildProjectIds[childProjectId]; seen {
+				continue
+			}
+			seenChildProjectIds[childProjectId] = struct{}{}
+			merged.IncludedChildProjectIds = append(merged.IncludedChildProjectIds, childProjectId)
+		}
+
+		for projectId, subtotal := range stat.SubtotalsByProjectId {
+			merged.SubtotalsByProjectId[projectId] = subtotal
+		}
+	}
+
+	return merged
+}
+
+func FilterAutoscale(stat shared.Autoscale, keep func(projectID string) bool) shared.Autoscale {
+	filtered := shared.Autoscale{
+		SubtotalsByProjectId: make(map[string]shared.AutoscaleProject, len(stat.SubtotalsByProjectId)),
+	}
+
+	for _, activation := range stat.ActivationHistory {
+		if keep(activation.ProjectID) {
+			filtered.ActivationHistory = append(filtered.ActivationHistory, activation)
+		}
+	}
+
+	for _, childProjectId := range stat.IncludedChildProjectIds {
+		if keep(childProjectId) {
+			filtered.IncludedChildProjectIds = append(filtered.IncludedChildProjectIds, childProjectId)
+		}
+	}
+
+	for _, event := range stat.ScalingHistory {
+		if keep(event.ProjectID) {
+			filtered.ScalingHistory = append(filtered.ScalingHistory, event)
+		}
+	}
+
+	for projectId, subtotal := range stat.SubtotalsByProjectId {
+		if keep(projectId) {
+			filtered.SubtotalsByProjectId[projectId] = subtotal
+		}
+	}
+
+	return filtered
+}
+
 func RootProjectName(project shared.Projects) string {
 	s := project.OrganizationId
 	if project.ProjectID == project.OrganizationId {
@@ -74,25 +151,41 @@ func RootProjectName(project shared.Projects) string {
 
 func GetRootProjectIDs(projects []shared.Projects) []string {
 	var rootProjectIDs []string
+	seen := make(map[string]struct{}, len(projects))
 	for _, project := range projects {
-		rootProjectName := RootProjectName(project)
-		if rootProjectName != "" {
+		rootProjectID := RootProjectName(project)
+		if rootProjectID == "" {
+			rootProjectID = project.ProjectID
+		}
+		if _, ok := seen[rootProjectID]; ok {
 			continue
-		} else {
-			rootProjectIDs = append(rootProjectIDs, project.ProjectID)
 		}
+		seen[rootProjectID] = struct{}{}
+		rootProjectIDs = append(rootProjectIDs, rootProjectID)
 	}
 	return rootProjectIDs
 }
 
+func CurrentScaleLimits(activation shared.AutoscaleActivationHistory) (int, int) {
+	minInstances, maxInstances := activation.MinInstances, activation.MaxInstances
+	var latest int64
+	for _, change := range activation.ScaleLimitsChanges {
+		if change.ChangedAt >= latest {
+			latest = change.ChangedAt
+			minInstances, maxInstances = change.MinInstances, change.MaxInstances
+		}
+	}
+	return minInstances, maxInstances
+}
+
 func MillisToSeconds(ms int64) float64 {
 	return float64(ms) / 1000.0
 }
 
-func GetCertValidityDatesInSeconds(base64Cert string) (int64, int64, error) {
+func ParseBase64Certificate(base64Cert string) (*x509.Certificate, error) {
 	certBytes, err := base64.StdEncoding.DecodeString(base64Cert)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to decode base64: %v", err)
+		return nil, fmt.Errorf("failed to decode base64: %v", err)
 	}
 
 	block, _ := pem.Decode(certBytes)
@@ -102,12 +195,34 @@ func GetCertValidityDatesInSeconds(base64Cert string) (int64, int64, error) {
 
 	cert, err := x509.ParseCertificate(certBytes)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to parse certificate: %v", err)
+		return nil, fmt.Errorf("failed to parse certificate: %v", err)
+	}
+
+	return cert, nil
+}
+
+func GetCertValidityDatesInSeconds(base64Cert string) (int64, int64, error) {
+	cert, err := ParseBase64Certificate(base64Cert)
+	if err != nil {
+		return 0, 0, err
 	}
 
 	return cert.NotBefore.Unix(), cert.NotAfter.Unix(), nil
 }
 
+func CertificateIssuerType(cert *x509.Certificate) string {
+	for _, organization := range cert.Issuer.Organization {
+		if strings.Contains(strings.ToLower(organization), "let's encrypt") {
+			return "letsencrypt"
+		}
+	}
+	return "custom"
+}
+
+func MiBToBytes(mib int64) int64 {
+	return mib * 1024 * 1024
+}
+
 func GiBToBytes(gib int64) int64 {
 	return gib * 1024 * 1024 * 1024
 }
@@ -116,6 +231,26 @@ func GBToBytes(gb int64) int64 {
 	return gb * 1000 * 1000 * 1000
 }
 
+func ImageVersion(image string) string {
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		return name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+func KeySetHash(keys []string) string {
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	sum := sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
 func StringToInt64(s string) int64 {
 	result, _ := strconv.ParseInt(s, 10, 64)
 	return result
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/jullianow/lcp-exporter/internal/shared"
 )
 
 func TestBoolToString(t *testing.T) {
@@ -22,3 +24,109 @@ func TestBoolToString(t *testing.T) {
 		})
 	}
 }
+
+func TestCurrentScaleLimits(t *testing.T) {
+	activation := shared.AutoscaleActivationHistory{MinInstances: 1, MaxInstances: 3}
+
+	minInstances, maxInstances := CurrentScaleLimits(activation)
+	assert.Equal(t, 1, minInstances)
+	assert.Equal(t, 3, maxInstances)
+
+	activation.ScaleLimitsChanges = []shared.AutoscaleScaleLimitsChange{
+		{ChangedAt: 300, MinInstances: 2, MaxInstances: 8},
+		{ChangedAt: 100, MinInstances: 1, MaxInstances: 5},
+	}
+
+	minInstances, maxInstances = CurrentScaleLimits(activation)
+	assert.Equal(t, 2, minInstances)
+	assert.Equal(t, 8, maxInstances)
+}
+
+func TestBoolToFloat(t *testing.T) {
+	assert.Equal(t, 1.0, BoolToFloat(true))
+	assert.Equal(t, 0.0, BoolToFloat(false))
+}
+
+func TestChunkStrings(t *testing.T) {
+	list := []string{"a", "b", "c", "d", "e"}
+
+	assert.Nil(t, ChunkStrings(nil, 2))
+	assert.Equal(t, [][]string{list}, ChunkStrings(list, 0))
+	assert.Equal(t, [][]string{list}, ChunkStrings(list, 10))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, ChunkStrings(list, 2))
+}
+
+func TestMergeAutoscale(t *testing.T) {
+	merged := MergeAutoscale([]shared.Autoscale{
+		{
+			ActivationHistory:       []shared.AutoscaleActivationHistory{{ProjectID: "a-prd"}},
+			IncludedChildProjectIds: []string{"a-prd", "a-uat"},
+			ScalingHistory:          []shared.AutoscaleScalingHistory{{ProjectID: "a-prd"}},
+			SubtotalsByProjectId: map[string]shared.AutoscaleProject{
+				"a-prd": {BillableTimeMs: 1},
+				"a-uat": {BillableTimeMs: 2},
+			},
+		},
+		{
+			IncludedChildProjectIds: []string{"b-prd", "a-prd"},
+			ScalingHistory:          []shared.AutoscaleScalingHistory{{ProjectID: "b-prd"}},
+			SubtotalsByProjectId: map[string]shared.AutoscaleProject{
+				"b-prd": {BillableTimeMs: 3},
+			},
+		},
+	})
+
+	assert.Equal(t, []string{"a-prd", "a-uat", "b-prd"}, merged.IncludedChildProjectIds)
+	assert.Len(t, merged.SubtotalsByProjectId, 3)
+	assert.Equal(t, int64(3), merged.SubtotalsByProjectId["b-prd"].BillableTimeMs)
+	assert.Len(t, merged.ScalingHistory, 2)
+	assert.Len(t, merged.ActivationHistory, 1)
+}
+
+func TestFilterAutoscale(t *testing.T) {
+	filtered := FilterAutoscale(shared.Autoscale{
+		ActivationHistory:       []shared.AutoscaleActivationHistory{{ProjectID: "a-prd"}, {ProjectID: "a-dev"}},
+		IncludedChildProjectIds: []string{"a-prd", "a-dev"},
+		ScalingHistory:          []shared.AutoscaleScalingHistory{{ProjectID: "a-dev"}},
+		SubtotalsByProjectId: map[string]shared.AutoscaleProject{
+			"a-prd": {BillableTimeMs: 1},
+			"a-dev": {BillableTimeMs: 2},
+		},
+	}, func(projectID string) bool { return projectID != "a-dev" })
+
+	assert.Equal(t, []string{"a-prd"}, filtered.IncludedChildProjectIds)
+	assert.Equal(t, map[string]shared.AutoscaleProject{"a-prd": {BillableTimeMs: 1}}, filtered.SubtotalsByProjectId)
+	assert.Empty(t, filtered.ScalingHistory)
+	assert.Equal(t, []shared.AutoscaleActivationHistory{{ProjectID: "a-prd"}}, filtered.ActivationHistory)
+}
+
+func TestGetRootProjectIDs(t *testing.T) {
+	projects := []shared.Projects{
+		{ProjectID: "a", OrganizationId: "a"},
+		{ProjectID: "a-prd", OrganizationId: "a"},
+		{ProjectID: "b-prd", OrganizationId: "b"},
+		{ProjectID: "c"},
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, GetRootProjectIDs(projects))
+}
+
+func TestImageVersion(t *testing.T) {
+	assert.Equal(t, "7.4.13-u112", ImageVersion("liferaycloud/liferay-dxp:7.4.13-u112"))
+	assert.Equal(t, "5.2.0", ImageVersion("registry.example.com:5000/liferaycloud/nginx:5.2.0"))
+	assert.Equal(t, "sha256:abc", ImageVersion("liferaycloud/database@sha256:abc"))
+	assert.Equal(t, "", ImageVersion("liferaycloud/backup"))
+	assert.Equal(t, "", ImageVersion(""))
+}
+
+func TestMiBToBytes(t *testing.T) {
+	assert.Equal(t, int64(1048576), MiBToBytes(1))
+}
+
+func TestKeySetHash(t *testing.T) {
+	hash := KeySetHash([]string{"B", "A", "C"})
+	assert.Len(t, hash, 16)
+	assert.Equal(t, hash, KeySetHash([]string{"C", "A", "B", "A"}))
+	assert.NotEqual(t, hash, KeySetHash([]string{"A", "B"}))
+	assert.Equal(t, KeySetHash(nil), KeySetHash([]string{}))
+}
@@ -1,30 +1,87 @@
 package lcp
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/jullianow/lcp-exporter/internal"
 )
 
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// StatusError deliberately carries no response body: some endpoints return
+// secret values, and errors end up in the logs.
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
 type Client struct {
 	BaseURL     string
 	BearerToken string
 	Client      *http.Client
+	Retry       RetryPolicy
+
+	retries          *prometheus.CounterVec
+	retriesExhausted prometheus.Counter
 }
 
 func NewClient(baseURL, bearerToken string) *Client {
+	fqName := internal.ExporterName("client")
+
 	return &Client{
 		BaseURL:     baseURL,
 		BearerToken: bearerToken,
-		Client:      &http.Client{Timeout: 10 * time.Second},
+		Client:      &http.Client{},
+		Retry: RetryPolicy{
+			MaxRetries: 3,
+			BaseDelay:  500 * time.Millisecond,
+			MaxDelay:   10 * time.Second,
+		},
+		retries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fqName("retries_total"),
+				Help: "Total number of retried LCP API requests by reason",
+			},
+			[]string{"reason"},
+		),
+		retriesExhausted: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: fqName("retries_exhausted_total"),
+				Help: "Total number of LCP API requests that failed after all retries",
+			},
+		),
 	}
 }
 
+func (c *Client) Describe(ch chan<- *prometheus.Desc) {
+	c.retries.Describe(ch)
+	c.retriesExhausted.Describe(ch)
+}
+
+func (c *Client) Collect(ch chan<- prometheus.Metric) {
+	c.retries.Collect(ch)
+	c.retriesExhausted.Collect(ch)
+}
+
 func (c *Client) buildURL(path string, queryParams map[string]string) string {
 	baseURL := fmt.Sprintf("%s%s", c.BaseURL, path)
 
@@ -40,10 +97,53 @@ func (c *Client) buildURL(path string, queryParams map[string]string) string {
 	return baseURL
 }
 
-func (c *Client) MakeRequest(path string, queryParams map[string]string) (*http.Response, error) {
+func (c *Client) MakeRequest(ctx context.Context, path string, queryParams map[string]string) (*http.Response, error) {
 	url := c.buildURL(path, queryParams)
 
-	req, err := http.NewRequest("GET", url, nil)
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, url)
+		if err == nil {
+			return resp, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		reason, retryable := retryReason(err)
+		if !retryable {
+			return nil, err
+		}
+
+		if attempt >= c.Retry.MaxRetries {
+			if attempt > 0 {
+				c.retriesExhausted.Inc()
+			}
+			return nil, err
+		}
+
+		var retryAfter time.Duration
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			retryAfter = statusErr.RetryAfter
+		}
+
+		delay := c.Retry.backoff(attempt, retryAfter)
+		c.retries.WithLabelValues(reason).Inc()
+		internal.LogWarn("MakeRequest", "Retrying %s in %s (attempt %d/%d): %v", path, delay, attempt+1, c.Retry.MaxRetries, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -57,27 +157,84 @@ func (c *Client) MakeRequest(path string, queryParams map[string]string) (*http.
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
+		_, _ = io.Copy(io.Discard, resp.Body)
 		if cerr := resp.Body.Close(); cerr != nil {
 			internal.LogWarn("MakeRequest", "Error closing response body: %v", cerr)
 		}
-		internal.LogWarn("MakeRequest", "Non-2xx response from %s: %d - %s", url, resp.StatusCode, string(body))
-		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+		internal.LogWarn("MakeRequest", "Non-2xx response from %s: %d", url, resp.StatusCode)
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
 	return resp, nil
 }
 
+func retryReason(err error) (string, bool) {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return "transport", true
+	}
+
+	switch {
+	case statusErr.StatusCode == http.StatusTooManyRequests:
+		return strconv.Itoa(statusErr.StatusCode), true
+	case statusErr.StatusCode >= 500 && statusErr.StatusCode != http.StatusNotImplemented:
+		return strconv.Itoa(statusErr.StatusCode), true
+	default:
+		return "", false
+	}
+}
+
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, p.MaxDelay)
+	}
+
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
+
 func ParseEnvelope[T any](body []byte) ([]T, error) {
+	// The envelope message is ignored: it may echo request values back.
 	var envelope struct {
-		Status  int             `json:"status"`
-		Message string          `json:"message"`
-		Data    json.RawMessage `json:"data"`
+		Status int             `json:"status"`
+		Data   json.RawMessage `json:"data"`
 	}
 	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Data != nil {
 		if envelope.Status != 0 && envelope.Status != http.StatusOK {
-			internal.LogWarn("ParseEnvelope", "API error status: %d - %s", envelope.Status, envelope.Message)
-			return nil, fmt.Errorf("API error %d: %s", envelope.Status, envelope.Message)
+			internal.LogWarn("ParseEnvelope", "API error status: %d", envelope.Status)
+			return nil, fmt.Errorf("API error %d", envelope.Status)
 		}
 
 		var dataSlice []T
@@ -115,16 +272,19 @@ func ParseEnvelope[T any](body []byte) ([]T, error) {
 	}
 
 	var single T
-	if err := json.Unmarshal(body, &single); err == nil {
+	err := json.Unmarshal(body, &single)
+	if err == nil {
 		return []T{single}, nil
 	}
 
-	internal.LogError("ParseEnvelope", "Failed to unmarshal response body: %s", string(body))
-	return nil, fmt.Errorf("failed to parse response")
+	// Only the decoder error is logged; it names the field and type but never
+	// the value, whereas the body may hold secrets.
+	internal.LogError("ParseEnvelope", "Failed to unmarshal response body (%d bytes): %v", len(body), err)
+	return nil, fmt.Errorf("failed to parse response: %w", err)
 }
 
-func FetchFrom[T any](c *Client, path string, queryParams map[string]string) ([]T, error) {
-	resp, err := c.MakeRequest(path, queryParams)
+func FetchFrom[T any](ctx context.Context, c *Client, path string, queryParams map[string]string) ([]T, error) {
+	resp, err := c.MakeRequest(ctx, path, queryParams)
 	if err != nil {
 		internal.LogError("FetchFrom", "Request failed for path %s: %v", path, err)
 		return nil, err
@@ -144,8 +304,8 @@ func FetchFrom[T any](c *Client, path string, queryParams map[string]string) ([]
 	return ParseEnvelope[T](body)
 }
 
-func FetchOneFrom[T any](c *Client, path string, queryParams map[string]string) (*T, error) {
-	results, err := FetchFrom[T](c, path, queryParams)
+func FetchOneFrom[T any](ctx context.Context, c *Client, path string, queryParams map[string]string) (*T, error) {
+	results, err := FetchFrom[T](ctx, c, path, queryParams)
 	if err != nil {
 		return nil, err
 	}
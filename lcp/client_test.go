@@ -1,11 +1,15 @@
 package lcp
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,7 +27,7 @@ func TestFetchFrom_Slice(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	result, err := FetchFrom[TestData](client, "/", nil)
+	result, err := FetchFrom[TestData](context.Background(), client, "/", nil)
 	require.NoError(t, err)
 	require.Len(t, result, 2)
 	require.Equal(t, "foo", result[0].Name)
@@ -38,7 +42,7 @@ func TestFetchFrom_Map(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	result, err := FetchFrom[TestData](client, "/map", nil)
+	result, err := FetchFrom[TestData](context.Background(), client, "/map", nil)
 	require.NoError(t, err)
 	require.Len(t, result, 1)
 	require.Equal(t, "baz", result[0].Name)
@@ -53,7 +57,7 @@ func TestFetchFrom_SingleObject(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	result, err := FetchFrom[TestData](client, "/single", nil)
+	result, err := FetchFrom[TestData](context.Background(), client, "/single", nil)
 	require.NoError(t, err)
 	require.Len(t, result, 1)
 	require.Equal(t, "solo", result[0].Name)
@@ -68,7 +72,7 @@ func TestFetchFrom_EnvelopeWithDataSlice(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	result, err := FetchFrom[TestData](client, "/envelope-slice", nil)
+	result, err := FetchFrom[TestData](context.Background(), client, "/envelope-slice", nil)
 	require.NoError(t, err)
 	require.Len(t, result, 1)
 	require.Equal(t, "env", result[0].Name)
@@ -83,7 +87,7 @@ func TestFetchFrom_EnvelopeWithDataMap(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	result, err := FetchFrom[TestData](client, "/envelope-map", nil)
+	result, err := FetchFrom[TestData](context.Background(), client, "/envelope-map", nil)
 	require.NoError(t, err)
 	require.Len(t, result, 1)
 	require.Equal(t, "mapped", result[0].Name)
@@ -98,7 +102,7 @@ func TestFetchFrom_EnvelopeWithErrorStatus(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	_, err := FetchFrom[TestData](client, "/error-envelope", nil)
+	_, err := FetchFrom[TestData](context.Background(), client, "/error-envelope", nil)
 	require.Error(t, err)
 }
 
@@ -111,7 +115,7 @@ func TestFetchFrom_InvalidJSON(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	_, err := FetchFrom[TestData](client, "/invalid-json", nil)
+	_, err := FetchFrom[TestData](context.Background(), client, "/invalid-json", nil)
 	require.Error(t, err)
 }
 
@@ -125,7 +129,7 @@ func TestFetchFrom_APIErrorStatus(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	_, err := FetchFrom[TestData](client, "/forbidden", nil)
+	_, err := FetchFrom[TestData](context.Background(), client, "/forbidden", nil)
 	require.Error(t, err)
 }
 
@@ -138,8 +142,173 @@ func TestFetchOneFrom(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL, "dummy-token")
-	result, err := FetchOneFrom[TestData](client, "/one", nil)
+	result, err := FetchOneFrom[TestData](context.Background(), client, "/one", nil)
 	require.NoError(t, err)
 	require.NotNil(t, result)
 	require.Equal(t, "one", result.Name)
 }
+
+func newFlakyServer(t *testing.T, failures int64, status int, header http.Header) (*httptest.Server, *atomic.Int64) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, err := io.WriteString(w, `{"name":"ok","value":1}`)
+		require.NoError(t, err)
+	}))
+	return server, &calls
+}
+
+func newRetryClient(url string, maxRetries int) *Client {
+	client := NewClient(url, "dummy-token")
+	client.Retry = RetryPolicy{
+		MaxRetries: maxRetries,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   5 * time.Millisecond,
+	}
+	return client
+}
+
+func TestMakeRequest_RetriesUntilSuccess(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	client := newRetryClient(server.URL, 3)
+	result, err := FetchOneFrom[TestData](context.Background(), client, "/retry", nil)
+	require.NoError(t, err)
+	require.Equal(t, "ok", result.Name)
+	require.Equal(t, int64(3), calls.Load())
+	require.Equal(t, 2.0, testutil.ToFloat64(client.retries.WithLabelValues("502")))
+	require.Equal(t, 0.0, testutil.ToFloat64(client.retriesExhausted))
+}
+
+func TestMakeRequest_RetriesExhausted(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	client := newRetryClient(server.URL, 2)
+	_, err := FetchFrom[TestData](context.Background(), client, "/exhausted", nil)
+	require.Error(t, err)
+	require.Equal(t, int64(3), calls.Load())
+	require.Equal(t, 2.0, testutil.ToFloat64(client.retries.WithLabelValues("503")))
+	require.Equal(t, 1.0, testutil.ToFloat64(client.retriesExhausted))
+}
+
+func TestMakeRequest_DoesNotRetryClientErrors(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusNotFound, nil)
+	defer server.Close()
+
+	client := newRetryClient(server.URL, 3)
+	_, err := FetchFrom[TestData](context.Background(), client, "/not-found", nil)
+	require.Error(t, err)
+	require.Equal(t, int64(1), calls.Load())
+	require.Equal(t, 0.0, testutil.ToFloat64(client.retriesExhausted))
+}
+
+func TestMakeRequest_RespectsRetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"1"}}
+	server, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, header)
+	defer server.Close()
+
+	client := newRetryClient(server.URL, 1)
+	client.Retry.MaxDelay = 2 * time.Second
+
+	start := time.Now()
+	_, err := FetchFrom[TestData](context.Background(), client, "/rate-limited", nil)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+	require.Equal(t, int64(2), calls.Load())
+	require.Equal(t, 1.0, testutil.ToFloat64(client.retries.WithLabelValues("429")))
+}
+
+func TestMakeRequest_RetriesTransportErrors(t *testing.T) {
+	server, _ := newFlakyServer(t, 0, http.StatusOK, nil)
+	url := server.URL
+	server.Close()
+
+	client := newRetryClient(url, 2)
+	_, err := FetchFrom[TestData](context.Background(), client, "/closed", nil)
+	require.Error(t, err)
+	require.Equal(t, 2.0, testutil.ToFloat64(client.retries.WithLabelValues("transport")))
+	require.Equal(t, 1.0, testutil.ToFloat64(client.retriesExhausted))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 0; attempt < 6; attempt++ {
+		expected := min(policy.BaseDelay<<attempt, policy.MaxDelay)
+		delay := policy.backoff(attempt, 0)
+		require.GreaterOrEqual(t, delay, expected/2)
+		require.LessOrEqual(t, delay, expected)
+	}
+
+	require.Equal(t, 300*time.Millisecond, policy.backoff(0, 300*time.Millisecond))
+	require.Equal(t, time.Second, policy.backoff(0, time.Minute))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-1", 0},
+		{"invalid", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-30 * time.Second).Format(http.TimeFormat), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			require.Equal(t, tt.expected, parseRetryAfter(tt.value, now))
+		})
+	}
+}
+
+func TestMakeRequest_CanceledContextStopsRetries(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	client := newRetryClient(server.URL, 5)
+	client.Retry.BaseDelay = time.Second
+	client.Retry.MaxDelay = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := FetchFrom[TestData](ctx, client, "/canceled", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, int64(1), calls.Load())
+}
+
+func TestMakeRequest_CanceledContextAbortsInFlightRequest(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := newRetryClient(server.URL, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := FetchFrom[TestData](ctx, client, "/slow", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 0.0, testutil.ToFloat64(client.retriesExhausted))
+}
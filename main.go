@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"html/template"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -12,6 +15,9 @@ import (
 	"github.com/jullianow/lcp-exporter/collector/admin"
 	"github.com/jullianow/lcp-exporter/config"
 	"github.com/jullianow/lcp-exporter/internal"
+	"github.com/jullianow/lcp-exporter/internal/cursor"
+	"github.com/jullianow/lcp-exporter/internal/events"
+	"github.com/jullianow/lcp-exporter/internal/scheduler"
 	"github.com/jullianow/lcp-exporter/lcp"
 )
 
@@ -61,8 +67,15 @@ func main() {
 	cfg := config.ParseFlags()
 
 	client := lcp.NewClient(cfg.Endpoint, cfg.Token)
+	client.Client.Timeout = cfg.RequestTimeout
+	client.Retry = lcp.RetryPolicy{
+		MaxRetries: cfg.MaxRetries,
+		BaseDelay:  cfg.RetryBaseDelay,
+		MaxDelay:   cfg.RetryMaxDelay,
+	}
 
 	registry := prometheus.NewRegistry()
+	registry.MustRegister(client)
 
 	if !cfg.EnableGoMetrics {
 		internal.LogInfo("Main", "Disabling Go default metrics")
@@ -80,52 +93,196 @@ func main() {
 		http.Handle(cfg.MetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 	}
 
-	dataRange := internal.CalculateDates(cfg.Duration)
-	projectsCollector := admin.NewProjectsCollector(client)
+	autoscaleOptions := admin.AutoscaleOptions{
+		Window:        internal.DateWindow{Mode: cfg.AutoscaleWindow, Duration: cfg.Duration},
+		BillingPeriod: cfg.EnableAutoscaleBillingPeriod,
+		LegacyHistory: cfg.EnableAutoscaleLegacyHistory,
+		BatchSize:     cfg.AutoscaleBatchSize,
+		Concurrency:   cfg.AutoscaleConcurrency,
+	}
+
+	if cfg.AutoscaleBudgetsFile != "" {
+		budgets, err := config.LoadBudgets(cfg.AutoscaleBudgetsFile)
+		if err != nil {
+			internal.LogFatal("Main", "Failed to load autoscale budgets: %v", err)
+		}
+		autoscaleOptions.Budgets = budgets
+	}
+
+	if cfg.ExchangeRatesFile != "" {
+		exchangeRates, err := config.NewExchangeRatesFile(cfg.ExchangeRatesFile)
+		if err != nil {
+			internal.LogFatal("Main", "Failed to load exchange rates: %v", err)
+		}
+		go exchangeRates.Watch(context.Background(), cfg.ExchangeRatesReloadInterval)
+		autoscaleOptions.ExchangeRates = exchangeRates
+	}
+
+	var eventSinks []events.Sink
+	if cfg.EventsLog {
+		eventSinks = append(eventSinks, events.NewLogSink(os.Stdout))
+	}
+	if cfg.EventsWebhookURL != "" {
+		eventSinks = append(eventSinks, events.NewWebhookSink(cfg.EventsWebhookURL))
+	}
+	if len(eventSinks) > 0 {
+		eventCursor, err := cursor.Open(cfg.EventsCursorFile)
+		if err != nil {
+			internal.LogFatal("Main", "Failed to load event cursor: %v", err)
+		}
+		internal.LogInfo("Main", "Publishing autoscale events to %d sinks", len(eventSinks))
+		autoscaleOptions.Events = events.NewDispatcher(eventCursor, eventSinks...)
+	}
+
+	ctx := context.Background()
+
+	var projectFilter *config.ProjectFilter
+	if cfg.ProjectFilterFile != "" {
+		filter, err := config.LoadProjectFilter(cfg.ProjectFilterFile)
+		if err != nil {
+			internal.LogFatal("Main", "Failed to load project filter: %v", err)
+		}
+		projectFilter = filter
+	}
+
+	inventory := admin.NewProjectInventory(client, cfg.InventoryTTL, projectFilter)
+	registry.MustRegister(inventory)
+
+	activityCursor, err := cursor.Open(cfg.ActivitiesCursorFile)
+	if err != nil {
+		internal.LogFatal("Main", "Failed to load activity cursor: %v", err)
+	}
+
+	activitiesOptions := admin.ActivitiesOptions{
+		Cursor:      activityCursor,
+		Concurrency: cfg.ProjectConcurrency,
+	}
+	if cfg.ActivitiesLog {
+		activitiesOptions.Sinks = append(activitiesOptions.Sinks, events.NewLogSink(os.Stdout))
+	}
 
 	collectorConfigs := []struct {
 		name      string
-		collector prometheus.Collector
+		collector scheduler.Source
 		enable    bool
+		interval  time.Duration
 	}{
 		{
 			name:      "projects",
-			collector: projectsCollector,
+			collector: admin.NewProjectsCollector(inventory),
 			enable:    true,
+			interval:  cfg.ProjectsInterval,
 		},
 		{
 			name:      "autoscale",
-			collector: admin.NewAutoscaleCollector(client, projectsCollector, dataRange),
+			collector: admin.NewAutoscaleCollector(client, inventory, autoscaleOptions),
 			enable:    true,
+			interval:  cfg.AutoscaleInterval,
+		},
+		{
+			name:      "services",
+			collector: admin.NewServicesCollector(client, inventory, cfg.ProjectConcurrency),
+			enable:    cfg.EnableServicesMetrics,
+			interval:  cfg.ServicesInterval,
+		},
+		{
+			name:      "deployments",
+			collector: admin.NewDeploymentsCollector(client, inventory, internal.DateWindow{Mode: cfg.DeploymentsWindow, Duration: cfg.Duration}, cfg.ProjectConcurrency),
+			enable:    cfg.EnableDeploymentsMetrics,
+			interval:  cfg.DeploymentsInterval,
+		},
+		{
+			name:      "backups",
+			collector: admin.NewBackupsCollector(client, inventory, cfg.ProjectConcurrency),
+			enable:    cfg.EnableBackupsMetrics,
+			interval:  cfg.BackupsInterval,
+		},
+		{
+			name:      "custom_domains",
+			collector: admin.NewCustomDomainsCollector(client, inventory, cfg.ProjectConcurrency),
+			enable:    cfg.EnableCustomDomainsMetrics,
+			interval:  cfg.CustomDomainsInterval,
+		},
+		{
+			name:      "usage",
+			collector: admin.NewUsageCollector(client, inventory, cfg.UsageInterval, cfg.ProjectConcurrency),
+			enable:    cfg.EnableUsageMetrics,
+			interval:  cfg.UsageInterval,
+		},
+		{
+			name:      "team",
+			collector: admin.NewTeamCollector(client, inventory, cfg.ProjectConcurrency),
+			enable:    cfg.EnableTeamMetrics,
+			interval:  cfg.TeamInterval,
+		},
+		{
+			name:      "activities",
+			collector: admin.NewActivitiesCollector(client, inventory, activitiesOptions),
+			enable:    cfg.EnableActivitiesMetrics,
+			interval:  cfg.ActivitiesInterval,
+		},
+		{
+			name:      "environment",
+			collector: admin.NewEnvironmentCollector(client, inventory, cfg.ProjectConcurrency),
+			enable:    cfg.EnableEnvironmentMetrics,
+			interval:  cfg.EnvironmentInterval,
+		},
+		{
+			name:      "alerts",
+			collector: admin.NewAlertsCollector(client, inventory, internal.DateWindow{Mode: cfg.AlertsWindow, Duration: cfg.Duration}, cfg.ProjectConcurrency),
+			enable:    cfg.EnableAlertsMetrics,
+			interval:  cfg.AlertsInterval,
+		},
+		{
+			name:      "subscription",
+			collector: admin.NewSubscriptionCollector(client, inventory, cfg.ProjectConcurrency),
+			enable:    cfg.EnableSubscriptionMetrics,
+			interval:  cfg.SubscriptionInterval,
 		},
 		{
 			name:      "cluster_discovery",
 			collector: admin.NewClusterDiscoveryCollector(client),
 			enable:    cfg.EnableClusterDiscoveryMetrics,
+			interval:  cfg.ClusterDiscoveryInterval,
 		},
 		{
 			name:      "info",
 			collector: collector.NewInfoCollector(client),
 			enable:    true,
+			interval:  cfg.InfoInterval,
 		},
 		{
 			name:      "up",
 			collector: collector.NewUpCollector(client),
 			enable:    true,
+			interval:  cfg.UpInterval,
 		},
 	}
 
+	var jobs []*scheduler.Job
 	for _, config := range collectorConfigs {
 		if config.enable {
-			internal.LogInfo("Main", "Registering collector: %s", config.name)
-			registry.MustRegister(config.collector)
+			internal.LogInfo("Main", "Registering collector: %s (interval: %s)", config.name, config.interval)
+			job := scheduler.NewJob(config.name, config.collector, config.interval)
+			jobs = append(jobs, job)
 		}
 	}
 
+	// The first refreshes can take minutes, so they run in the background
+	// while the HTTP server already answers probes.
+	go func() {
+		if err := inventory.WarmUp(ctx); err != nil {
+			internal.LogError("Main", "Project inventory warm-up failed, collectors will retry on use: %v", err)
+		}
+		for _, job := range jobs {
+			go job.Run(ctx)
+		}
+	}()
+
 	mux := http.NewServeMux()
 	mux.Handle("/", http.HandlerFunc(handleRoot))
 	mux.Handle("/healthz", http.HandlerFunc(handleHealthz))
-	mux.Handle(cfg.MetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.Handle(cfg.MetricsPath, scheduler.Handler(registry, jobs, cfg.ScrapeTimeoutOffset, promhttp.HandlerOpts{}))
 
 	internal.LogInfo("Main", "Starting HTTP server on Port %s, serving metrics at %s. Version: %s", cfg.Port, cfg.MetricsPath, VERSION)
 